@@ -0,0 +1,478 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+// BinaryVersion 二进制数据格式的版本号
+const BinaryVersion = 1
+
+// 二进制数据格式的标记
+var binaryMagic = []byte("CNRB")
+
+const (
+	binaryNameSize = 12 // 名称记录的长度
+	binaryNodeSize = 20 // 节点记录的长度
+	binaryMaxYears = 32 // 二进制格式最多支持的年份数量
+)
+
+// 二进制格式的数据
+//
+// 数据格式如下，所有的整数均采用小端序：
+//
+//	magic[4] version[u8] years[u8] year[u16]...
+//	size[u32] strings[size]
+//	count[u32] name[count]
+//	count[u32] node[count]
+//
+//	- magic 固定为 CNRB；
+//	- version 为 BinaryVersion；
+//	- years 为年份的数量，其后跟随 years 个年份；
+//	- strings 为所有名称组成的字符串表；
+//	- name 为名称记录，由 offset[u32] length[u32] mask[u32] 组成，
+//	  offset 和 length 表示名称在字符串表中的位置，mask 表示使用该名称的年份；
+//	- node 为节点记录，由 id[u16] names[u16] size[u32] mask[u32] name[u32] first[u32] 组成，
+//	  id 为区域的 ID，names 为名称记录的数量，size 为子项的数量，mask 为支持的年份，
+//	  name 为第一个名称记录的索引，first 为第一个子项的索引；
+//
+// 节点按广度优先的顺序排列，第一个节点为根节点，同一节点的子项总是相邻的。
+type binaryData struct {
+	mux      sync.Mutex
+	versions []int // 数据中的年份列表
+	strings  []byte
+	names    []byte
+	nodes    []byte
+	close    func() error
+}
+
+type binaryNode struct {
+	id     uint16
+	names  uint16
+	size   uint32
+	mask   uint32
+	name   uint32
+	first  uint32
+	offset int // 节点在数据中的偏移量
+}
+
+// LoadBinary 将二进制格式的数据加载至 DB 对象
+//
+// 与 [Load] 不同，子项仅在第一次访问时才会解码，
+// 返回的对象会一直引用 data，调用方不能再修改 data 的内容。
+//
+// version 仅加载指定年份的数据，如果为空，则加载所有数据；
+func LoadBinary(data []byte, separator string, version ...int) (*DB, error) {
+	return loadBinary(data, newOptions([]Option{WithSeparator(separator), WithYears(version...)}))
+}
+
+func loadBinary(data []byte, o *options) (*DB, error) {
+	bin, err := parseBinary(data)
+	if err != nil {
+		return nil, err
+	}
+
+	version := o.years
+	for _, v := range version {
+		if slices.Index(bin.versions, v) == -1 {
+			return nil, fmt.Errorf("当前数据文件没有 %d 年份的数据", v)
+		}
+	}
+	if len(version) == 0 {
+		version = bin.versions
+	}
+
+	db := &DB{
+		versions:          slices.Clone(version),
+		fullNameSeparator: o.separator,
+		bin:               bin,
+		maxLevel:          o.maxLevel,
+		provinces:         o.provinces,
+	}
+	db.root = &Region{db: db}
+	db.root.versions = bin.filterVersions(db, bin.node(0).mask)
+	db.initDistricts()
+	db.initIndex(o.index)
+
+	return db, nil
+}
+
+// LoadBinaryFS 从二进制格式的数据文件加载数据
+func LoadBinaryFS(f fs.FS, file, separator string, version ...int) (*DB, error) {
+	data, err := fs.ReadFile(f, file)
+	if err != nil {
+		return nil, err
+	}
+	return LoadBinary(data, separator, version...)
+}
+
+// LoadBinaryFile 从二进制格式的数据文件加载数据
+//
+// 在支持的系统上会采用 mmap 映射文件内容，
+// 可以调用 [DB.Close] 释放映射的内存，之后该对象将不再可用。
+func LoadBinaryFile(file, separator string, version ...int) (*DB, error) {
+	data, closeFn, err := mmapFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := LoadBinary(data, separator, version...)
+	if err != nil {
+		if closeFn != nil {
+			closeFn()
+		}
+		return nil, err
+	}
+	db.bin.close = closeFn
+
+	return db, nil
+}
+
+// Close 释放由 [LoadBinaryFile] 映射的内存
+//
+// 调用之后，该对象及由其返回的所有区域都将不再可用。其它方式创建的对象调用此方法不会有任何操作。
+func (db *DB) Close() error {
+	if db.bin == nil || db.bin.close == nil {
+		return nil
+	}
+
+	err := db.bin.close()
+	db.bin.close = nil
+	return err
+}
+
+// DumpBinary 以二进制格式输出到文件
+//
+// 与 [DB.Dump] 相同，会以原子操作的方式替换 file。
+func (db *DB) DumpBinary(file string) error {
+	data, err := db.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return writeFile(file, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// MarshalBinary 将数据编码为二进制格式
+//
+// 返回的内容可以由 [LoadBinary] 加载。
+func (db *DB) MarshalBinary() ([]byte, error) {
+	if len(db.versions) > binaryMaxYears {
+		return nil, fmt.Errorf("二进制格式最多只支持 %d 个年份", binaryMaxYears)
+	}
+
+	// 按广度优先的顺序排列所有节点
+	nodes := []*Region{db.root}
+	for i := 0; i < len(nodes); i++ {
+		nodes = append(nodes, nodes[i].Items()...)
+	}
+
+	strs := make(map[string]uint32, 1000)
+	strings := bytes.Buffer{}
+	names := make([]byte, 0, len(nodes)*binaryNameSize)
+	nodeData := make([]byte, 0, len(nodes)*binaryNodeSize)
+	nameCount := 0
+	first := 1
+	for _, r := range nodes {
+		regionID := 0
+		if r.id != "" {
+			var err error
+			if regionID, err = strconv.Atoi(r.id); err != nil || regionID > math.MaxUint16 {
+				return nil, fmt.Errorf("无效的区域 ID %s", r.fullID)
+			}
+		}
+
+		mask, err := r.versionsMask(r.versions)
+		if err != nil {
+			return nil, err
+		}
+
+		history := r.NameHistory()
+		for _, n := range history {
+			offset, found := strs[n.Name]
+			if !found {
+				offset = uint32(strings.Len())
+				strings.WriteString(n.Name)
+				strs[n.Name] = offset
+			}
+
+			m, err := r.versionsMask(n.Versions)
+			if err != nil {
+				return nil, err
+			}
+			names = binary.LittleEndian.AppendUint32(names, offset)
+			names = binary.LittleEndian.AppendUint32(names, uint32(len(n.Name)))
+			names = binary.LittleEndian.AppendUint32(names, uint32(m))
+		}
+
+		items := r.Items()
+		nodeData = binary.LittleEndian.AppendUint16(nodeData, uint16(regionID))
+		nodeData = binary.LittleEndian.AppendUint16(nodeData, uint16(len(history)))
+		nodeData = binary.LittleEndian.AppendUint32(nodeData, uint32(len(items)))
+		nodeData = binary.LittleEndian.AppendUint32(nodeData, uint32(mask))
+		nodeData = binary.LittleEndian.AppendUint32(nodeData, uint32(nameCount))
+		nodeData = binary.LittleEndian.AppendUint32(nodeData, uint32(first))
+
+		nameCount += len(history)
+		first += len(items)
+	}
+
+	buf := make([]byte, 0, 20+len(db.versions)*2+strings.Len()+len(names)+len(nodeData))
+	buf = append(buf, binaryMagic...)
+	buf = append(buf, BinaryVersion, byte(len(db.versions)))
+	for _, v := range db.versions {
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(v))
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(strings.Len()))
+	buf = append(buf, strings.Bytes()...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(nameCount))
+	buf = append(buf, names...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(nodes)))
+	buf = append(buf, nodeData...)
+
+	return buf, nil
+}
+
+// 解析二进制数据的结构并验证所有节点的有效性
+func parseBinary(data []byte) (*binaryData, error) {
+	if !bytes.HasPrefix(data, binaryMagic) {
+		return nil, &SyntaxError{Offset: 0, Expected: string(binaryMagic)}
+	}
+	offset := len(binaryMagic)
+
+	if len(data) < offset+2 {
+		return nil, &SyntaxError{Offset: offset, Expected: "版本号"}
+	}
+	if data[offset] != BinaryVersion {
+		return nil, ErrIncompatible
+	}
+	years := int(data[offset+1])
+	offset += 2
+
+	if years > binaryMaxYears || len(data) < offset+years*2 {
+		return nil, &SyntaxError{Offset: offset, Expected: "年份列表"}
+	}
+	bin := &binaryData{versions: make([]int, 0, years)}
+	for i := 0; i < years; i++ {
+		bin.versions = append(bin.versions, int(binary.LittleEndian.Uint16(data[offset:])))
+		offset += 2
+	}
+
+	table := func(size int, expected string) ([]byte, error) {
+		if len(data) < offset+4 {
+			return nil, &SyntaxError{Offset: offset, Expected: expected}
+		}
+		l := int(binary.LittleEndian.Uint32(data[offset:]))
+		if l < 0 || l > (len(data)-offset-4)/size {
+			return nil, &SyntaxError{Offset: offset, Expected: expected}
+		}
+		offset += 4
+		t := data[offset : offset+l*size]
+		offset += l * size
+		return t, nil
+	}
+
+	var err error
+	if bin.strings, err = table(1, "字符串表"); err != nil {
+		return nil, err
+	}
+	if bin.names, err = table(binaryNameSize, "名称表"); err != nil {
+		return nil, err
+	}
+	nodesOffset := offset + 4
+	if bin.nodes, err = table(binaryNodeSize, "节点表"); err != nil {
+		return nil, err
+	}
+	if offset != len(data) {
+		return nil, &SyntaxError{Offset: offset, Expected: "文件结尾"}
+	}
+
+	if err := bin.validate(nodesOffset); err != nil {
+		return nil, err
+	}
+	return bin, nil
+}
+
+// 验证所有的节点，保证在延迟解码时不会出错。
+//
+// offset 为节点表在数据中的偏移量，仅用于生成错误信息。
+func (bin *binaryData) validate(offset int) error {
+	count := len(bin.nodes) / binaryNodeSize
+	if count == 0 {
+		return &SyntaxError{Offset: offset, Expected: "根节点"}
+	}
+
+	nameCount := len(bin.names) / binaryNameSize
+	for i := 0; i < nameCount; i++ {
+		o := binary.LittleEndian.Uint32(bin.names[i*binaryNameSize:])
+		l := binary.LittleEndian.Uint32(bin.names[i*binaryNameSize+4:])
+		if uint64(o)+uint64(l) > uint64(len(bin.strings)) {
+			return &SyntaxError{Offset: offset, Expected: "名称记录"}
+		}
+	}
+
+	levels := make([]id.Level, count) // 各个节点的级别
+	next := 1                         // 下一个子项的索引
+	for i := 0; i < count; i++ {
+		n := bin.node(uint32(i))
+		level := levels[i]
+		errorf := func(expected string) error {
+			return &SyntaxError{Offset: offset + n.offset, Expected: expected}
+		}
+
+		if (level == 0 && n.id != 0) || (level != 0 && int(n.id) >= pow10(idWidth(level))) {
+			return errorf("区域 ID")
+		}
+
+		if n.names == 0 || uint64(n.name)+uint64(n.names) > uint64(nameCount) {
+			return errorf("名称索引")
+		}
+
+		if n.size > 0 {
+			if level == id.Village || int(n.first) != next || uint64(n.first)+uint64(n.size) > uint64(count) {
+				return errorf("子项索引")
+			}
+
+			child := id.Province
+			if level != 0 {
+				child = level >> 1
+			}
+			for j := n.first; j < n.first+n.size; j++ {
+				levels[j] = child
+			}
+			next += int(n.size)
+		}
+	}
+
+	if next != count {
+		return &SyntaxError{Offset: offset, Expected: "节点数量"}
+	}
+	return nil
+}
+
+func (bin *binaryData) node(index uint32) *binaryNode {
+	offset := int(index) * binaryNodeSize
+	data := bin.nodes[offset:]
+	return &binaryNode{
+		id:     binary.LittleEndian.Uint16(data),
+		names:  binary.LittleEndian.Uint16(data[2:]),
+		size:   binary.LittleEndian.Uint32(data[4:]),
+		mask:   binary.LittleEndian.Uint32(data[8:]),
+		name:   binary.LittleEndian.Uint32(data[12:]),
+		first:  binary.LittleEndian.Uint32(data[16:]),
+		offset: offset,
+	}
+}
+
+// 解码 reg 的子项
+func (bin *binaryData) decodeItems(reg *Region) []*Region {
+	n := bin.node(reg.node)
+	if n.size == 0 {
+		return nil
+	}
+
+	level := id.Province
+	parentID := ""
+	if reg.level != 0 {
+		level = reg.level >> 1
+		parentID = reg.fullID[:id.Length(reg.level)]
+	}
+	if !reg.db.isLevelLoaded(level) {
+		return nil
+	}
+	width := idWidth(level)
+
+	items := make([]*Region, 0, n.size)
+	for i := n.first; i < n.first+n.size; i++ {
+		c := bin.node(i)
+		versions := bin.filterVersions(reg.db, c.mask)
+		if len(versions) == 0 {
+			continue
+		}
+
+		regionID := fmt.Sprintf("%0*d", width, c.id)
+		if level == id.Province && !reg.db.isProvinceLoaded(regionID) {
+			continue
+		}
+
+		item := &Region{
+			id:       regionID,
+			versions: versions,
+			db:       reg.db,
+			level:    level,
+			parent:   reg,
+			node:     i,
+		}
+		item.fullID = id.Fill(parentID+item.id, id.Village)
+
+		for j := c.name; j < c.name+uint32(c.names); j++ {
+			data := bin.names[int(j)*binaryNameSize:]
+			o := binary.LittleEndian.Uint32(data)
+			l := binary.LittleEndian.Uint32(data[4:])
+			name := string(bin.strings[o : o+l])
+			if vers := bin.filterVersions(reg.db, binary.LittleEndian.Uint32(data[8:])); len(vers) > 0 {
+				item.names = append(item.names, &NameRecord{Name: name, Versions: vers})
+			}
+		}
+		switch len(item.names) {
+		case 0: // 数据错误，没有任何名称与年份对应。
+		case 1:
+			item.name = item.names[0].Name
+			item.names = nil
+		default:
+			item.name = item.NameAt(slices.Max(item.versions))
+		}
+
+		item.fullName = item.name
+		if reg.level != 0 {
+			item.fullName = reg.fullName + reg.db.fullNameSeparator + item.name
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// 将 mask 转换为年份列表，并过滤掉 db 中不存在的年份。
+func (bin *binaryData) filterVersions(db *DB, mask uint32) []int {
+	versions := make([]int, 0, len(db.versions))
+	for i, v := range bin.versions {
+		if flag := uint32(1) << i; flag&mask == flag && slices.Index(db.versions, v) > -1 {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// 区域 ID 在 level 级别中所占的位数
+func idWidth(level id.Level) int {
+	if level == id.Province {
+		return id.Length(level)
+	}
+	return id.Length(level) - id.Length(level<<1)
+}
+
+func pow10(n int) int {
+	v := 1
+	for i := 0; i < n; i++ {
+		v *= 10
+	}
+	return v
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+func TestDB_MarshalBinary(t *testing.T) {
+	a := assert.New(t, false)
+
+	bin, err := obj.MarshalBinary()
+	a.NotError(err).NotNil(bin)
+
+	db, err := LoadBinary(bin, "-")
+	a.NotError(err).NotNil(db).
+		Equal(db.Versions(), obj.Versions()).
+		False(db.root.items[0].loaded.Load())
+
+	// 与文本格式的内容相同
+	text, err := db.marshal()
+	a.NotError(err).Equal(string(text), string(data))
+
+	r := db.Find("340300000000")
+	a.NotNil(r).
+		Equal(r.ID(), "03").
+		Equal(r.Name(), "芜湖-2").
+		Equal(r.FullName(), "安徽-芜湖-2").
+		Equal(r.Level(), id.City).
+		Equal(r.Versions(), []int{2020}).
+		Equal(r.Parent().FullID(), "340000000000").
+		Empty(r.Items())
+	a.Length(db.Districts(), len(districtsMap))
+
+	// 名称的历史记录
+	db2 := newNamesDB(a)
+	bin, err = db2.MarshalBinary()
+	a.NotError(err).NotNil(bin)
+
+	db, err = LoadBinary(bin, "-")
+	a.NotError(err).NotNil(db)
+	r = db.Find("330305000000")
+	a.NotNil(r).
+		Equal(r.Name(), "洞头区").
+		Equal(r.FullName(), "浙江省-温州市-洞头区").
+		Equal(r.FullNameAt(2019), "浙江省-温州地区-洞头县")
+
+	// 指定年份
+	db, err = LoadBinary(bin, "-", 2019)
+	a.NotError(err).NotNil(db).Equal(db.Versions(), []int{2019})
+	r = db.Find("330305000000")
+	a.NotNil(r).
+		Equal(r.Name(), "洞头县").
+		Equal(r.FullName(), "浙江省-温州地区-洞头县").
+		Equal(r.Versions(), []int{2019}).
+		Length(r.NameHistory(), 1)
+
+	_, err = LoadBinary(bin, "-", 2001)
+	a.ErrorString(err, "2001")
+}
+
+func TestLoadBinaryFile(t *testing.T) {
+	a := assert.New(t, false)
+
+	path := filepath.Join(t.TempDir(), "regions.bin")
+	a.NotError(obj.DumpBinary(path))
+
+	db, err := LoadBinaryFile(path, "-", 2020)
+	a.NotError(err).NotNil(db)
+	r := db.Find("330100000000")
+	a.NotNil(r).Equal(r.FullName(), "浙江-温州")
+	a.NotError(db.Close()).NotError(db.Close())
+
+	db, err = LoadBinaryFS(os.DirFS(filepath.Dir(path)), "regions.bin", "-", 2019)
+	a.NotError(err).NotNil(db)
+	a.Empty(db.Provinces()).Nil(db.Find("330100000000"))
+
+	// 空文件
+	path = filepath.Join(t.TempDir(), "empty.bin")
+	a.NotError(os.WriteFile(path, nil, os.ModePerm))
+	_, err = LoadBinaryFile(path, "-")
+	a.Error(err)
+}
+
+func TestLoadBinary_error(t *testing.T) {
+	a := assert.New(t, false)
+
+	bin, err := obj.MarshalBinary()
+	a.NotError(err).NotNil(bin)
+
+	var serr *SyntaxError
+	_, err = LoadBinary(bin[1:], "-")
+	a.True(errors.As(err, &serr)).Equal(serr.Offset, 0)
+
+	_, err = LoadBinary(bin[:len(bin)-1], "-")
+	a.True(errors.As(err, &serr)).Equal(serr.Expected, "节点表")
+
+	_, err = LoadBinary(append(bin, 0), "-")
+	a.True(errors.As(err, &serr)).Equal(serr.Expected, "文件结尾")
+
+	data := append([]byte{}, bin...)
+	data[4] = BinaryVersion + 1
+	_, err = LoadBinary(data, "-")
+	a.Equal(err, ErrIncompatible)
+
+	// 修改第一个子项的索引
+	data = append([]byte{}, bin...)
+	data[len(data)-7*binaryNodeSize+16] = 5
+	_, err = LoadBinary(data, "-")
+	a.True(errors.As(err, &serr)).Equal(serr.Expected, "子项索引")
+}
+
+func FuzzLoadBinary(f *testing.F) {
+	bin, err := obj.MarshalBinary()
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(bin)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		db, err := LoadBinary(data, "-")
+		if err != nil {
+			return
+		}
+
+		if _, err := db.marshal(); err != nil {
+			t.Errorf("marshal 返回了错误 %s", err)
+		}
+	})
+}
+
+func TestLoadBinary_addItem(t *testing.T) {
+	a := assert.New(t, false)
+
+	bin, err := obj.MarshalBinary()
+	a.NotError(err)
+	db, err := LoadBinary(bin, "-")
+	a.NotError(err).NotNil(db)
+
+	a.NotError(db.AddItem("3302", "新市", 2020))
+	city := db.Find("330200000000")
+	a.NotNil(city).Empty(city.Items())
+
+	a.NotError(db.AddItem("330201", "海曙区", 2020)).
+		NotError(db.AddItem("330201001", "鼓楼街道", 2020)).
+		NotError(db.AddItem("330201001001", "新村", 2020))
+	a.Empty(db.Find("330201001001").Items()).
+		Length(city.Items(), 1).
+		Equal(city.Items()[0].FullName(), "浙江-新市-海曙区")
+
+	a.NotPanic(func() {
+		a.Length(db.Search(&Options{Text: "新"}), 2)
+	})
+
+	db2 := reloadDB(a, db)
+	a.Equal(db2.Find("330201001001").FullName(), "浙江-新市-海曙区-鼓楼街道-新村").
+		Empty(db2.Find("330201001001").Items()).
+		Length(db2.Find("330200000000").Items(), 1).
+		Length(db2.Find("330000000000").Items(), 2)
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/issue9/cnregion/v2"
+)
+
+type change struct {
+	Type   string `json:"type"`
+	FullID string `json:"fullID"`
+	Level  string `json:"level"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+// 输出 from 至 to 年份之间的区域变更
+//
+// format 为输出的格式，可以是 text、csv 和 json。
+func diff(w io.Writer, file string, from, to int, format string) error {
+	d, err := cnregion.LoadFile(file, "-", true, from, to)
+	if err != nil {
+		return err
+	}
+
+	changes := d.Diff(from, to)
+	list := make([]*change, 0, len(changes))
+	for _, c := range changes {
+		list = append(list, &change{
+			Type:   c.Type.String(),
+			FullID: c.FullID,
+			Level:  c.Level.String(),
+			From:   c.From,
+			To:     c.To,
+		})
+	}
+
+	switch format {
+	case "text", "":
+		return diffText(w, list)
+	case "csv":
+		return diffCSV(w, list)
+	case "json":
+		e := json.NewEncoder(w)
+		e.SetIndent("", "\t")
+		return e.Encode(list)
+	default:
+		return fmt.Errorf("不支持的输出格式 %s", format)
+	}
+}
+
+func diffText(w io.Writer, list []*change) error {
+	for _, c := range list {
+		var err error
+		switch c.Type {
+		case cnregion.Added.String():
+			_, err = fmt.Fprintf(w, "+ %s %-8s %s\n", c.FullID, c.Level, c.To)
+		case cnregion.Removed.String():
+			_, err = fmt.Fprintf(w, "- %s %-8s %s\n", c.FullID, c.Level, c.From)
+		default:
+			_, err = fmt.Fprintf(w, "~ %s %-8s %s => %s\n", c.FullID, c.Level, c.From, c.To)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "共 %d 条变更\n", len(list))
+	return err
+}
+
+func diffCSV(w io.Writer, list []*change) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"type", "full_id", "level", "from", "to"}); err != nil {
+		return err
+	}
+
+	for _, c := range list {
+		if err := cw.Write([]string{c.Type, c.FullID, c.Level, c.From, c.To}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
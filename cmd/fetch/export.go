@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/issue9/cnregion/v2"
+	"github.com/issue9/cnregion/v2/export"
+	"github.com/issue9/cnregion/v2/id"
+)
+
+// 将数据文件导出为其它格式
+//
+// format 可以是 json、csv、sql、copy 和 schema；
+// level 为最大的区域级别名称，空值表示所有级别；
+// output 为空表示输出到 w。
+func exportData(w io.Writer, file, format, level, table, output string, year int) error {
+	maxLevel, err := id.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	if maxLevel != 0 && maxLevel&(maxLevel-1) != 0 {
+		return fmt.Errorf("max-level 只能指定一个区域级别，当前为 %s", level)
+	}
+
+	var f func(io.Writer, *cnregion.DB, *export.Options) error
+	switch format {
+	case "json", "":
+		f = export.JSON
+	case "csv":
+		f = export.CSV
+	case "sql":
+		f = export.SQL
+	case "copy":
+		f = export.Copy
+	case "schema":
+	default:
+		return fmt.Errorf("不支持的输出格式 %s", format)
+	}
+
+	o := &export.Options{Year: year, MaxLevel: maxLevel, Table: table}
+
+	var d *cnregion.DB
+	if f != nil { // 先加载数据，以免在出错时生成空的输出文件。
+		if d, err = cnregion.Open(file); err != nil {
+			return err
+		}
+	}
+
+	write := func(w io.Writer) error {
+		if f == nil {
+			return export.Schema(w, o)
+		}
+		return f(w, d, o)
+	}
+
+	if output == "" {
+		return write(w)
+	}
+	return writeFile(output, write)
+}
+
+// 将 write 的内容写入 file
+//
+// 与 cnregion 中保存数据文件的方式相同：内容先写入同目录下的临时文件，
+// 同步至磁盘之后再重命名为 file，出错时不会修改已经存在的 file。
+func writeFile(file string, write func(io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp) // 重命名成功之后，此操作不会有任何效果。
+
+	w := bufio.NewWriter(f)
+	if err = write(w); err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, 0o644)
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, file)
+}
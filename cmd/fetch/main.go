@@ -33,6 +33,12 @@ func main() {
 
 	opt.New("build", "生成数据\n", "生成数据\n", doBuild)
 
+	opt.New("diff", "比较两个年份之间的数据\n", "比较两个年份之间的数据\n", doDiff)
+
+	opt.New("export", "导出为其它格式\n", "导出为其它格式\n", doExport)
+
+	opt.New("merge", "合并多个数据文件\n", "合并多个数据文件，格式为 merge -output=c.db a.db b.db\n", doMerge)
+
 	if err := opt.Exec(os.Args[1:]); err != nil {
 		fmt.Fprintln(os.Stdout, err)
 		os.Exit(2)
@@ -84,6 +90,53 @@ func doBuild(fs *flag.FlagSet) cmdopt.DoFunc {
 	}
 }
 
+func doDiff(fs *flag.FlagSet) cmdopt.DoFunc {
+	var (
+		diffInput  string
+		diffFrom   int
+		diffTo     int
+		diffFormat string
+	)
+	fs.StringVar(&diffInput, "input", "../../data/regions.db", "指定数据文件")
+	fs.IntVar(&diffFrom, "from", 0, "指定起始年份")
+	fs.IntVar(&diffTo, "to", 0, "指定结束年份")
+	fs.StringVar(&diffFormat, "format", "text", "指定输出格式，可以是 text、csv 和 json。")
+
+	return func(w io.Writer) error {
+		return diff(w, diffInput, diffFrom, diffTo, diffFormat)
+	}
+}
+
+func doExport(fs *flag.FlagSet) cmdopt.DoFunc {
+	var (
+		exportInput  string
+		exportFormat string
+		exportYear   int
+		exportLevel  string
+		exportTable  string
+		exportOutput string
+	)
+	fs.StringVar(&exportInput, "input", "../../data/regions.db", "指定数据文件")
+	fs.StringVar(&exportFormat, "format", "json", "指定输出格式，可以是 json、csv、sql、copy 和 schema。")
+	fs.IntVar(&exportYear, "year", 0, "指定年份，0 表示所有年份。")
+	fs.StringVar(&exportLevel, "max-level", "", "指定最大的区域级别，可以是 province、city、county、town 和 village。")
+	fs.StringVar(&exportTable, "table", "", "指定 SQL 的表名")
+	fs.StringVar(&exportOutput, "output", "", "指定输出文件，空值表示输出到标准输出。")
+
+	return func(w io.Writer) error {
+		return exportData(w, exportInput, exportFormat, exportLevel, exportTable, exportOutput, exportYear)
+	}
+}
+
+func doMerge(fs *flag.FlagSet) cmdopt.DoFunc {
+	var mergeOutput string
+	fs.StringVar(&mergeOutput, "output", "", "指定输出文件路径")
+
+	return func(w io.Writer) error {
+		return merge(w, mergeOutput, fs.Args()...)
+	}
+}
+
 func getYears(years string) ([]int, error) {
 	if years == "" {
 		return nil, nil
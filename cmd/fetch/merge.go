@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/issue9/cnregion/v2"
+	"github.com/issue9/term/v3/colors"
+)
+
+// 合并多个数据文件并保存至 output
+//
+// 名称冲突时保留先出现的文件中的名称，冲突信息会输出到 w。
+func merge(w io.Writer, output string, files ...string) error {
+	if len(files) < 2 {
+		return errors.New("至少需要指定两个数据文件")
+	}
+	if output == "" {
+		return errors.New("未指定输出文件")
+	}
+
+	d, err := cnregion.Open(files[0])
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files[1:] {
+		other, err := cnregion.Open(file)
+		if err != nil {
+			return err
+		}
+
+		err = d.Merge(other)
+		if err == nil {
+			continue
+		}
+
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() { // Merge 返回的是 errors.Join 合并的错误
+			var conflict *cnregion.MergeConflict
+			if !errors.As(e, &conflict) {
+				return err
+			}
+		}
+		fmt.Fprintln(w, colorsSprintf(colors.Yellow, "合并 %s 时存在冲突，保留之前的名称：\n%s", file, err))
+	}
+
+	return d.Dump(output, true)
+}
@@ -12,79 +12,158 @@
 package cnregion
 
 import (
+	"bufio"
 	"bytes"
 	"compress/gzip"
 	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
 )
 
-// LoadFS 从数据文件加载数据
-func LoadFS(f fs.FS, file, separator string, compress bool, version ...int) (*DB, error) {
-	data, err := fs.ReadFile(f, file)
+// Open 从数据文件加载数据
+//
+// src 为数据文件的路径，默认从本地文件系统读取，可以通过 [WithFS] 指定其它文件系统。
+// 文件的格式以及是否压缩会自动判断，具体可参考 [LoadReader]。
+func Open(src string, opts ...Option) (*DB, error) {
+	o := newOptions(opts)
+
+	var r io.ReadCloser
+	var err error
+	if o.fs != nil {
+		r, err = o.fs.Open(src)
+	} else {
+		r, err = os.Open(src)
+	}
 	if err != nil {
 		return nil, err
 	}
-	return Load(data, separator, compress, version...)
+	defer r.Close()
+
+	return loadReader(r, o)
+}
+
+// LoadFS 从数据文件加载数据
+//
+// 功能与 [Open] 相同，仅为兼容保留。
+func LoadFS(f fs.FS, file, separator string, compress bool, version ...int) (*DB, error) {
+	return Open(file, WithFS(f), WithSeparator(separator), WithYears(version...))
 }
 
 // Load 将数据内容加载至 DB 对象
 //
 // version 仅加载指定年份的数据，如果为空，则加载所有数据；
+// compress 仅为兼容保留，是否为压缩数据会根据内容自动判断。
+//
+// 功能与 [LoadReader] 相同，仅为兼容保留。
 func Load(data []byte, separator string, compress bool, version ...int) (*DB, error) {
-	if compress {
-		rd, err := gzip.NewReader(bytes.NewReader(data))
+	return LoadReader(bytes.NewReader(data), WithSeparator(separator), WithYears(version...))
+}
+
+// LoadFile 从数据文件加载数据
+//
+// 功能与 [Open] 相同，仅为兼容保留。
+func LoadFile(file, separator string, compress bool, version ...int) (*DB, error) {
+	return Open(file, WithSeparator(separator), WithYears(version...))
+}
+
+// LoadReader 从 r 中加载数据
+//
+// 文本格式的数据会边读取边解析，无需将整个内容读入内存。
+// 如果内容是由 gzip 压缩的，会自动解压；如果内容是二进制格式，则会读取所有内容之后由 [LoadBinary] 加载。
+func LoadReader(r io.Reader, opts ...Option) (*DB, error) { return loadReader(r, newOptions(opts)) }
+
+func loadReader(r io.Reader, o *options) (*DB, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b { // gzip
+		gr, err := gzip.NewReader(br)
 		if err != nil {
 			return nil, err
 		}
+		defer gr.Close()
+		br = bufio.NewReader(gr)
+	}
 
-		data, err = io.ReadAll(rd)
+	if magic, err := br.Peek(len(binaryMagic)); err == nil && bytes.Equal(magic, binaryMagic) {
+		data, err := io.ReadAll(br)
 		if err != nil {
 			return nil, err
 		}
+		return loadBinary(data, o)
 	}
 
 	db := &DB{
-		fullNameSeparator: separator,
-		filters:           version,
+		fullNameSeparator: o.separator,
+		filters:           o.years,
+		maxLevel:          o.maxLevel,
+		provinces:         o.provinces,
 	}
-	if err := db.unmarshal(data); err != nil {
+	if err := db.unmarshal(br); err != nil {
 		return nil, err
 	}
 
 	db.initDistricts()
+	db.initIndex(o.index)
 
 	return db, nil
 }
 
-// LoadFile 从数据文件加载数据
-func LoadFile(file, separator string, compress bool, version ...int) (*DB, error) {
-	data, err := os.ReadFile(file)
-	if err != nil {
-		return nil, err
-	}
-	return Load(data, separator, compress, version...)
-}
-
 // Dump 输出到文件
+//
+// 内容会先写入同目录下的临时文件，完成之后再替换 file，
+// 即使中途出错也不会留下只写入了部分内容的文件。
 func (db *DB) Dump(file string, compress bool) error {
+	return writeFile(file, func(w io.Writer) error { return db.DumpTo(w, compress) })
+}
+
+// DumpTo 以文本格式将数据写入 w
+//
+// compress 表示是否采用 gzip 进行压缩。
+func (db *DB) DumpTo(w io.Writer, compress bool) error {
 	data, err := db.marshal()
 	if err != nil {
 		return err
 	}
 
-	if compress {
-		buf := new(bytes.Buffer)
-		w := gzip.NewWriter(buf)
-		if _, err = w.Write(data); err != nil {
-			return err
-		}
-		if err = w.Close(); err != nil {
-			return err
-		}
+	if !compress {
+		_, err = w.Write(data)
+		return err
+	}
+
+	gw := gzip.NewWriter(w)
+	if _, err = gw.Write(data); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// 以原子操作的方式将内容写入 file
+//
+// write 的内容先写入同目录下的临时文件，成功之后再重命名为 file。
+func writeFile(file string, write func(io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp) // 重命名成功之后，此操作不会有任何效果。
 
-		data = buf.Bytes()
+	w := bufio.NewWriter(f)
+	if err = write(w); err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, 0o644)
+	}
+	if err != nil {
+		return err
 	}
 
-	return os.WriteFile(file, data, os.ModePerm)
+	return os.Rename(tmp, file)
 }
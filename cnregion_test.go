@@ -5,9 +5,18 @@
 package cnregion
 
 import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"testing/iotest"
 
 	"github.com/issue9/assert/v4"
 
@@ -38,11 +47,133 @@ func TestLoad(t *testing.T) {
 	_, err = Load([]byte("100:[2020]:::1:0{}"), "-", false)
 	a.Equal(err, ErrIncompatible)
 
+	// 版本 1 的数据格式
+	o1, err = Load([]byte("1:[2020]:::1:1{33:浙江@1:1:0{}}"), "-", false)
+	a.NotError(err).NotNil(o1).
+		Equal(o1.Find("330000000000").Name(), "浙江@1")
+
 	o1, err = Load(data, "-", false, 2019)
 	a.NotError(err).
 		Equal(0, len(o1.root.items))
 }
 
+func TestLoad_error(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, item := range []struct {
+		data     string
+		offset   int
+		path     string
+		expected string
+	}{
+		{data: "", offset: 0, expected: "':'"},
+		{data: "x:", offset: 0, expected: "整数"},
+		{data: "2:2020:", offset: 2, expected: "年份列表"},
+		{data: "2:[20x]:", offset: 2, expected: "年份列表"},
+		{data: "2:[2020]::", offset: 10, expected: "':'"},
+		{data: "3:[2020]:::1:1{33:浙江:1:0{}", offset: 30, expected: "'}'"},
+		{data: "3:[2020]:::1:1{33:浙江:1:0{}}}", offset: 31, expected: "文件结尾"},
+		{data: "3:[2020]:::1:1{33:浙江:x:0{}}", offset: 25, path: "33", expected: "整数"},
+		{data: "3:[2020]:::1:1{33:浙江:1:-1{}}", offset: 27, path: "33", expected: "子项数量"},
+		{data: "3:[2020]:::1:1{3:浙江:1:0{}}", offset: 15, expected: "区域 ID"},
+		{data: "3:[2020]:::1:1{33:浙江:1:0}", offset: 29, path: "33", expected: "'{'"},
+		{data: "3:[2020]:::1:2{33:浙江:1:0{}}", offset: 31, expected: "':'"},
+		{data: "3:[2020]:::1:1{33:浙江@x:1:0{}}", offset: 18, path: "33", expected: "名称"},
+	} {
+		_, err := Load([]byte(item.data), "-", false)
+		var serr *SyntaxError
+		a.True(errors.As(err, &serr), item.data).
+			Equal(serr.Offset, item.offset, item.data).
+			Equal(serr.Path, item.path, item.data).
+			Equal(serr.Expected, item.expected, item.data)
+	}
+
+	// 村级区域不能有子项
+	_, err := Load([]byte("3:[2020]:::1:1{33:a:1:1{01:b:1:1{01:c:1:1{001:d:1:1{001:e:1:1{}}}}}}"), "-", false)
+	a.ErrorString(err, "子项数量").ErrorString(err, "330101001001")
+
+	// 空的年份列表
+	db, err := Load([]byte("2:[]:::0:0{}"), "-", false)
+	a.NotError(err).NotNil(db).Empty(db.Versions())
+}
+
+func FuzzLoad(f *testing.F) {
+	f.Add(data)
+	f.Add([]byte("1:[2020]:::1:1{33:浙江:1:0{}}"))
+	f.Add([]byte("2:[2020,2019]:::3:1{33:浙江@1;浙@2:3:0{}}"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		db, err := Load(data, "-", false)
+		if err != nil {
+			return
+		}
+
+		if _, err := db.marshal(); err != nil {
+			t.Errorf("marshal 返回了错误 %s", err)
+		}
+	})
+}
+
+func TestLoadReader(t *testing.T) {
+	a := assert.New(t, false)
+
+	db, err := LoadReader(iotest.OneByteReader(bytes.NewReader(data)), WithSeparator(">"), WithYears(2019))
+	a.NotError(err).NotNil(db).
+		Equal(db.Versions(), []int{2019}).
+		Empty(db.Provinces())
+
+	// gzip
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	_, err = w.Write(data)
+	a.NotError(err).NotError(w.Close())
+	db, err = LoadReader(buf, WithSeparator(">"))
+	a.NotError(err).NotNil(db)
+	r := db.Find("340300000000")
+	a.NotNil(r).Equal(r.FullName(), "安徽>芜湖-2")
+
+	// 二进制格式
+	bin, err := obj.MarshalBinary()
+	a.NotError(err)
+	db, err = LoadReader(bytes.NewReader(bin), WithSeparator(">"))
+	a.NotError(err).NotNil(db).NotNil(db.bin)
+	r = db.Find("340300000000")
+	a.NotNil(r).Equal(r.FullName(), "安徽>芜湖-2")
+
+	// 超过缓存大小的字段
+	name := strings.Repeat(`\:`, 3000)
+	db, err = LoadReader(strings.NewReader("3:[2020]:::1:1{33:" + name + ":1:0{}}"))
+	a.NotError(err).NotNil(db).
+		Equal(db.Find("330000000000").Name(), strings.Repeat(`:`, 3000))
+
+	// 读取错误
+	_, err = LoadReader(iotest.ErrReader(io.ErrUnexpectedEOF))
+	a.ErrorIs(err, io.ErrUnexpectedEOF)
+	_, err = LoadReader(io.MultiReader(strings.NewReader("3:[2020]:::1:1{33:"), iotest.ErrReader(io.ErrClosedPipe)))
+	a.ErrorIs(err, io.ErrClosedPipe)
+}
+
+func TestOpen(t *testing.T) {
+	a := assert.New(t, false)
+
+	path := filepath.Join(t.TempDir(), "regions.db")
+	a.NotError(obj.Dump(path, true))
+
+	db, err := Open(path, WithSeparator(">"), WithYears(2020))
+	a.NotError(err).NotNil(db).Equal(db.Versions(), []int{2020})
+	r := db.Find("340300000000")
+	a.NotNil(r).Equal(r.FullName(), "安徽>芜湖-2")
+
+	fsys := fstest.MapFS{"regions.db": &fstest.MapFile{Data: data}}
+	db, err = Open("regions.db", WithFS(fsys))
+	a.NotError(err).NotNil(db).Equal(db.Versions(), []int{2020, 2019})
+	r = db.Find("340300000000")
+	a.NotNil(r).Equal(r.FullName(), "安徽芜湖-2")
+
+	_, err = Open("not-exists.db", WithFS(fsys))
+	a.ErrorIs(err, fs.ErrNotExist)
+}
+
 func TestDB_LoadDump(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -57,6 +188,55 @@ func TestDB_LoadDump(t *testing.T) {
 	a.NotError(err).NotNil(d)
 }
 
+func TestDB_DumpTo(t *testing.T) {
+	a := assert.New(t, false)
+
+	buf := new(bytes.Buffer)
+	a.NotError(obj.DumpTo(buf, false)).
+		Equal(buf.String(), string(data))
+
+	buf.Reset()
+	a.NotError(obj.DumpTo(buf, true))
+	db, err := LoadReader(buf)
+	a.NotError(err).NotNil(db).Equal(db.Versions(), obj.Versions())
+
+	a.ErrorIs(obj.DumpTo(errWriter{}, false), io.ErrShortWrite).
+		ErrorIs(obj.DumpTo(errWriter{}, true), io.ErrShortWrite)
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, io.ErrShortWrite }
+
+func TestDB_Dump(t *testing.T) {
+	a := assert.New(t, false)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "regions.db")
+	a.NotError(os.WriteFile(path, []byte("old"), 0o600))
+	a.NotError(obj.Dump(path, false))
+
+	content, err := os.ReadFile(path)
+	a.NotError(err).Equal(string(content), string(data))
+
+	// 写入失败时，不会修改原文件，也不会留下临时文件。
+	err = writeFile(path, func(w io.Writer) error {
+		_, err := w.Write([]byte("new"))
+		a.NotError(err)
+		return io.ErrUnexpectedEOF
+	})
+	a.ErrorIs(err, io.ErrUnexpectedEOF)
+	content, err = os.ReadFile(path)
+	a.NotError(err).Equal(string(content), string(data))
+	entries, err := os.ReadDir(dir)
+	a.NotError(err).Length(entries, 1)
+
+	if runtime.GOOS != "windows" {
+		stat, err := os.Stat(path)
+		a.NotError(err).Equal(stat.Mode().Perm(), fs.FileMode(0o644))
+	}
+}
+
 func TestLoadFS(t *testing.T) {
 	a := assert.New(t, false)
 
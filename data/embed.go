@@ -17,5 +17,10 @@ var data embed.FS
 //
 // 这样可以让程序不依赖外部文件，但同时也会增加编译后程序的大小。
 func Embed(separator string, version ...int) (*cnregion.DB, error) {
-	return cnregion.LoadFS(data, "regions.db", separator, true, version...)
+	return Open(cnregion.WithSeparator(separator), cnregion.WithYears(version...))
+}
+
+// Open 以指定的选项加载嵌入的 regions.db
+func Open(opts ...cnregion.Option) (*cnregion.DB, error) {
+	return cnregion.Open("regions.db", append(opts, cnregion.WithFS(data))...)
 }
@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/cnregion/v2"
 )
 
 func TestEmbed(t *testing.T) {
@@ -22,3 +24,12 @@ func TestEmbed(t *testing.T) {
 		Equal(r.Name(), "洞头区").
 		Equal(r.FullName(), "浙江省>温州市>洞头区")
 }
+
+func TestOpen(t *testing.T) {
+	a := assert.New(t, false)
+
+	v, err := Open(cnregion.WithSeparator(">"), cnregion.WithYears(2021))
+	a.NotError(err).NotNil(v).Equal(v.Versions(), []int{2021})
+	r := v.Find("330305000000")
+	a.NotNil(r).Equal(r.FullName(), "浙江省>温州市>洞头区")
+}
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"slices"
 	"strconv"
 	"strings"
@@ -18,25 +19,60 @@ import (
 )
 
 // Version 数据文件的版本号
-const Version = 1
+//
+// 可以读取小于等于此值的数据文件，但是写入时始终采用此版本。
+const Version = 3
 
 // ErrIncompatible 数据文件版本不兼容
 //
-// 当数据文件中指定的版本号与当前的 Version 不相等时，返回此错误。
+// 当数据文件中指定的版本号大于当前的 Version 或是为无效值时，返回此错误。
 var ErrIncompatible = errors.New("数据文件版本不兼容")
 
+// 由 [DB.AddItem] 等方法返回的错误
+var (
+	ErrParentNotFound = errors.New("上级区域不存在")
+	ErrInvalidID      = errors.New("无效的区域 ID")
+	ErrUnknownVersion = errors.New("不存在该年份的数据")
+)
+
+// SyntaxError 数据文件的格式错误
+type SyntaxError struct {
+	Offset   int    // 出错位置在数据中的偏移量，如果数据是压缩的，则为解压之后的偏移量。
+	Expected string // 期望的内容
+	Path     string // 出错区域的 ID，由各级区域的 ID 拼接而成，为空表示不在任何区域内。
+	Err      error  // 底层的错误信息，可以为空。
+}
+
+func (e *SyntaxError) Error() string {
+	msg := fmt.Sprintf("数据格式错误，位于 %d，期望 %s", e.Offset, e.Expected)
+	if e.Path != "" {
+		msg += "，区域 " + e.Path
+	}
+	if e.Err != nil {
+		msg += "：" + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *SyntaxError) Unwrap() error { return e.Err }
+
 // DB 区域数据库信息
 //
 // 数据格式：
 //
-//	1:[versions]:{id:name:yearIndex:size{}}
+//	3:[versions]:{id:name:yearIndex:size{}}
 //
-//	- 1 表示数据格式的版本，采用当前包的 Version 常量；
+//	- 3 表示数据格式的版本，采用当前包的 Version 常量；
 //	- versions 表示当前数据文件中的数据支持的年份列表，以逗号分隔；
 //	- id 当前区域的 ID；
-//	- name 当前区域的名称；
+//	- name 当前区域的名称，如果在不同年份中名称不同，则采用 name1@yearIndex1;name2@yearIndex2 的格式，
+//	  名称中的 \、:、{、}、@ 和 ; 需要添加 \ 进行转义；
 //	- yearIndex 此条数据支持的年份列表，每一个位表示一个年份在 versions 中的索引值；
 //	- size 表示子元素的数量；
+//
+// 只读的方法（包括 [Region] 和 [View] 的方法）可以在多个协程中同时调用；
+// 修改数据的方法，比如 [DB.AddItem]、[DB.Merge] 等，不能与其它任何方法同时调用。
+// 如果需要在运行过程中更新数据，可以采用 [Store] 整体替换 DB 对象。
 type DB struct {
 	root     *Region
 	versions []int // 支持的版本
@@ -45,16 +81,31 @@ type DB struct {
 
 	fullNameSeparator string
 	districts         []*Region
+	successors        map[string][]*succession
+	bin               *binaryData  // 二进制格式的数据，仅由 LoadBinary 等函数加载时才有值。
+	placeholder       *string      // 自动创建的上级区域的名称，为 nil 表示不自动创建。
+	index             *searchIndex // 名称的索引，为 nil 表示不使用索引。
+
+	// 加载时的过滤条件，二进制格式的数据在解码子项时也需要用到。
+	maxLevel  id.Level
+	provinces []string
 
 	// Load 指定的过滤版本，仅在 unmarshal 过程中使用，
 	// 在完成 unmarshal 之的清空。
 	filters []int
+
+	// 数据文件的格式版本，仅在 unmarshal 过程中使用。
+	format int
 }
 
 // NewDB 返回空的 [DB] 对象
-func NewDB() *DB {
-	db := &DB{versions: []int{}}
+//
+// opts 中仅 [WithSeparator]、[WithPlaceholder] 和 [WithSearchIndex] 有效。
+func NewDB(opts ...Option) *DB {
+	o := newOptions(opts)
+	db := &DB{versions: []int{}, fullNameSeparator: o.separator, placeholder: o.placeholder}
 	db.root = &Region{db: db}
+	db.initIndex(o.index)
 	return db
 }
 
@@ -74,21 +125,104 @@ func (db *DB) AddVersion(ver int) (ok bool) {
 // Find 查找指定 ID 对应的信息
 func (db *DB) Find(regionID string) *Region { return db.root.findItem(id.SplitFilter(regionID)...) }
 
+// Walk 按深度优先的顺序遍历所有指定级别的区域
+//
+// level 为需要遍历的区域级别，多个值可以通过或运算叠加，0 表示所有级别；
+// fn 为处理每个区域的函数，返回 false 表示中止遍历。
+func (db *DB) Walk(level id.Level, fn func(*Region) bool) {
+	if level == 0 {
+		level = id.AllLevel
+	}
+	db.root.walk(level, fn)
+}
+
 var levelIndex = []id.Level{id.Province, id.City, id.County, id.Town, id.Village}
 
 // AddItem 添加一条子项
+//
+// regionID 可以是完整的 12 位 ID，也可以是去掉后缀 0 的 ID，无效的 ID 返回 [ErrInvalidID]；
+// ver 必须已经通过 [DB.AddVersion] 添加，否则返回 [ErrUnknownVersion]。
+//
+// 如果 regionID 已经存在，则将 ver 添加到该区域支持的年份中，
+// 若 name 与该区域已有的名称不同，则作为该区域在 ver 年份中的名称。
+//
+// 如果上级区域不存在，返回 [ErrParentNotFound]，
+// 除非创建 db 时指定了 [WithPlaceholder]，此时会自动创建上级区域。
 func (db *DB) AddItem(regionID, name string, ver int) error {
-	list := id.SplitFilter(regionID)
-	item := db.root.findItem(list...)
+	fullID, err := normalizeID(regionID)
+	if err != nil {
+		return err
+	}
 
-	if item == nil {
-		items := list[:len(list)-1] // 上一级
-		item = db.root.findItem(items...)
-		level := levelIndex[len(items)]
-		return item.addItem(list[len(list)-1], name, level, ver)
+	if slices.Index(db.versions, ver) == -1 {
+		return fmt.Errorf("%w：%d", ErrUnknownVersion, ver)
 	}
 
-	return item.setSupported(ver)
+	db.resetIndex()
+	provinces := len(db.root.Items())
+	defer func() {
+		if db.districts != nil && len(db.root.items) != provinces { // 添加了新的省份
+			db.initDistricts()
+		}
+	}()
+
+	list := id.SplitFilter(fullID)
+	if item := db.root.findItem(list...); item != nil {
+		if item.placeholder { // 以真实的数据替换占位的区域
+			item.placeholder = false
+			item.name = name
+			item.names = nil
+			item.updateFullName()
+			return item.setSupported(ver)
+		}
+		return item.setName(name, ver)
+	}
+
+	parent := db.root
+	for i, regionID := range list[:len(list)-1] {
+		item := parent.findItem(regionID)
+		switch {
+		case item == nil && db.placeholder == nil:
+			return fmt.Errorf("%w：%s", ErrParentNotFound, id.Fill(strings.Join(list[:i+1], ""), id.Village))
+		case item == nil:
+			if err := parent.addItem(regionID, *db.placeholder, levelIndex[i], ver); err != nil {
+				return err
+			}
+			item = parent.items[len(parent.items)-1]
+			item.placeholder = true
+		case item.placeholder:
+			if err := item.setSupported(ver); err != nil {
+				return err
+			}
+		}
+		parent = item
+	}
+
+	return parent.addItem(list[len(list)-1], name, levelIndex[len(list)-1], ver)
+}
+
+// 将 ID 转换为 12 位的完整 ID
+//
+// regionID 可以是 12 位的完整 ID，也可以是去掉后缀 0 的 ID。
+func normalizeID(regionID string) (string, error) {
+	switch len(regionID) {
+	case id.Length(id.Province), id.Length(id.City), id.Length(id.County), id.Length(id.Town), id.Length(id.Village):
+	default:
+		return "", fmt.Errorf("%w：%s 的长度无效", ErrInvalidID, regionID)
+	}
+
+	for _, r := range regionID {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w：%s 中包含非数字的字符", ErrInvalidID, regionID)
+		}
+	}
+
+	fullID := id.Fill(regionID, id.Village)
+	list := id.SplitFilter(fullID)
+	if len(list) == 0 || id.Fill(strings.Join(list, ""), id.Village) != fullID {
+		return "", fmt.Errorf("%w：%s", ErrInvalidID, regionID)
+	}
+	return fullID, nil
 }
 
 func (db *DB) marshal() ([]byte, error) {
@@ -110,30 +244,40 @@ func (db *DB) marshal() ([]byte, error) {
 	}
 
 	if buf.Err != nil {
-		return nil, err
+		return nil, buf.Err
 	}
 	return buf.Bytes(), nil
 }
 
-func (db *DB) unmarshal(data []byte) error {
-	data, val := indexBytes(data, ':')
-	ver, err := strconv.Atoi(val)
+func (db *DB) unmarshal(r io.Reader) error {
+	d := newDecoder(r)
+
+	ver, err := d.int(':', "")
 	if err != nil {
 		return err
 	}
-	if ver != Version {
+	if ver < 1 || ver > Version {
 		return ErrIncompatible
 	}
+	db.format = ver
+	d.escaped = ver >= 3
 
-	data, val = indexBytes(data, ':')
-	versions := strings.Split(strings.Trim(val, "[]"), ",")
-	db.versions = make([]int, 0, len(versions))
-	for _, version := range versions {
-		v, err := strconv.Atoi(version)
-		if err != nil {
-			return err
+	val, err := d.field(':', "")
+	if err != nil {
+		return err
+	}
+	if len(val) < 2 || val[0] != '[' || val[len(val)-1] != ']' {
+		return d.error("年份列表", "", nil)
+	}
+	db.versions = make([]int, 0, 20)
+	if val = val[1 : len(val)-1]; val != "" {
+		for _, version := range strings.Split(val, ",") {
+			v, err := strconv.Atoi(version)
+			if err != nil {
+				return d.error("年份列表", "", err)
+			}
+			db.versions = append(db.versions, v)
 		}
-		db.versions = append(db.versions, v)
 	}
 
 	if len(db.filters) == 0 {
@@ -156,7 +300,21 @@ func (db *DB) unmarshal(data []byte) error {
 	}()
 
 	db.root = &Region{db: db}
-	return db.root.unmarshal(data, "", "", 0)
+	if err := db.root.unmarshal(d, "", "", 0); err != nil {
+		return err
+	}
+
+	return d.end()
+}
+
+// 是否需要加载 level 级别的区域
+func (db *DB) isLevelLoaded(level id.Level) bool {
+	return db.maxLevel == 0 || level >= db.maxLevel
+}
+
+// 是否需要加载 ID 为 provinceID 的省份
+func (db *DB) isProvinceLoaded(provinceID string) bool {
+	return len(db.provinces) == 0 || slices.Index(db.provinces, provinceID) > -1
 }
 
 func (db *DB) filterVersions(versions []int) []int {
@@ -13,7 +13,7 @@ import (
 	"github.com/issue9/cnregion/v2/version"
 )
 
-var data = []byte(`1:[2020,2019]:::1:2{33:浙江:1:1{01:温州:3:0{}}34:安徽:1:3{01:合肥:3:0{}02:芜湖:1:0{}03:芜湖-2:1:0{}}}`)
+var data = []byte(`3:[2020,2019]:::1:2{33:浙江:1:1{01:温州:3:0{}}34:安徽:1:3{01:合肥:3:0{}02:芜湖:1:0{}03:芜湖-2:1:0{}}}`)
 
 var obj = &DB{
 	versions:          []int{2020, 2019},
@@ -85,10 +85,68 @@ func init() {
 func setRegionDB(r *Region, db *DB) {
 	r.db = db
 	for _, i := range r.items {
+		i.parent = r
 		setRegionDB(i, db)
 	}
 }
 
+func TestDB_AddItem(t *testing.T) {
+	a := assert.New(t, false)
+
+	db := NewDB(WithSeparator("-"))
+	a.True(db.AddVersion(2020))
+	a.NotError(db.AddItem("330000000000", "浙江", 2020)).
+		NotError(db.AddItem("330300000000", "温州", 2020)).
+		NotError(db.AddItem("330305000000", "洞头", 2020))
+
+	r := db.Find("330305000000")
+	a.NotNil(r).
+		Equal(r.FullID(), "330305000000").
+		Equal(r.FullName(), "浙江-温州-洞头").
+		Equal(r.Parent().FullID(), "330300000000").
+		Equal(r.Parent().Parent().FullName(), "浙江").
+		Nil(r.Parent().Parent().Parent())
+
+	a.NotError(db.AddItem("3303", "温州", 2020))
+	a.ErrorIs(db.AddItem("3303051", "洞头", 2020), ErrInvalidID).
+		ErrorIs(db.AddItem("33a3", "温州", 2020), ErrInvalidID).
+		ErrorIs(db.AddItem("330005", "洞头", 2020), ErrInvalidID).
+		ErrorIs(db.AddItem("000000000000", "洞头", 2020), ErrInvalidID).
+		ErrorIs(db.AddItem("3304", "湖州", 2019), ErrUnknownVersion).
+		ErrorIs(db.AddItem("330302001001", "五马", 2020), ErrParentNotFound).
+		ErrorIs(db.AddItem("3401", "合肥", 2020), ErrParentNotFound).
+		Nil(db.Find("340000000000"))
+}
+
+func TestDB_AddItem_placeholder(t *testing.T) {
+	a := assert.New(t, false)
+
+	db := NewDB(WithSeparator("-"), WithPlaceholder("?"))
+	a.True(db.AddVersion(2020)).True(db.AddVersion(2019))
+
+	a.NotError(db.AddItem("330305001000", "东屏", 2020))
+	r := db.Find("330305001000")
+	a.NotNil(r).
+		Equal(r.FullName(), "?-?-?-东屏").
+		Equal(r.Parent().Level(), id.County).
+		True(r.Parent().IsSupported(2020))
+
+	a.NotError(db.AddItem("330305002000", "北岙", 2019))
+	a.True(r.Parent().IsSupported(2019)).
+		True(db.Find("330000000000").IsSupported(2019))
+
+	// 以真实的数据替换占位区域
+	a.NotError(db.AddItem("33", "浙江", 2020)).
+		NotError(db.AddItem("3303", "温州", 2020)).
+		NotError(db.AddItem("330305", "洞头", 2020))
+	a.Equal(r.FullName(), "浙江-温州-洞头-东屏").
+		Equal(db.Find("330305002000").FullName(), "浙江-温州-洞头-北岙").
+		Equal(db.Find("330000000000").Versions(), []int{2020, 2019})
+
+	// 已经是真实的数据
+	a.ErrorString(db.AddItem("33", "浙江省", 2020), "已经存在名称")
+}
+
 func TestDB_Find(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -130,3 +188,38 @@ func TestDB_Find(t *testing.T) {
 		Equal(r.ID(), "05").
 		Contains(r.Versions(), []int{2018, 2017, 2016, 2015})
 }
+
+func TestDB_Walk(t *testing.T) {
+	a := assert.New(t, false)
+
+	ids := make([]string, 0, 10)
+	obj.Walk(id.Province, func(r *Region) bool {
+		a.Equal(r.Level(), id.Province)
+		ids = append(ids, r.FullID())
+		return true
+	})
+	a.Equal(ids, []string{"330000000000", "340000000000"})
+
+	ids = ids[:0]
+	obj.Walk(id.City|id.Town, func(r *Region) bool {
+		ids = append(ids, r.FullID())
+		return true
+	})
+	a.Equal(ids, []string{"330100000000", "340100000000", "340200000000", "340300000000"})
+
+	// 所有级别
+	ids = ids[:0]
+	obj.Walk(0, func(r *Region) bool {
+		ids = append(ids, r.FullID())
+		return true
+	})
+	a.Length(ids, 6)
+
+	// 中止
+	ids = ids[:0]
+	obj.Walk(id.City, func(r *Region) bool {
+		ids = append(ids, r.FullID())
+		return len(ids) < 2
+	})
+	a.Equal(ids, []string{"330100000000", "340100000000"})
+}
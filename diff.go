@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import "github.com/issue9/cnregion/v2/id"
+
+// ChangeType 区域变更的类型
+type ChangeType int8
+
+// 对区域变更类型的定义
+const (
+	Added   ChangeType = iota + 1 // 新增的区域
+	Removed                       // 被删除的区域
+	Renamed                       // 名称有变化的区域
+)
+
+// Change 两个年份之间的区域变更记录
+type Change struct {
+	Type   ChangeType
+	FullID string
+	Level  id.Level
+	From   string // 变更前的名称，Type 为 Added 时为空。
+	To     string // 变更后的名称，Type 为 Removed 时为空。
+	Region *Region
+}
+
+func (t ChangeType) String() string {
+	switch t {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Renamed:
+		return "renamed"
+	default:
+		return "unknown"
+	}
+}
+
+// Diff 比较两个年份之间的区域变更
+//
+// 返回的变更记录按深度优先的顺序排列，新增或是删除的区域，其子项也会一并返回。
+// 如果 from 或是 to 不在 [DB.Versions] 之中，那么该年份被当作没有任何区域数据。
+func (db *DB) Diff(from, to int) []Change {
+	changes := make([]Change, 0, 100)
+
+	db.Walk(id.AllLevel, func(r *Region) bool {
+		f, t := r.NameAt(from), r.NameAt(to)
+		c := Change{FullID: r.fullID, Level: r.level, From: f, To: t, Region: r}
+
+		switch {
+		case f == "" && t != "":
+			c.Type = Added
+		case f != "" && t == "":
+			c.Type = Removed
+		case f != t:
+			c.Type = Renamed
+		default:
+			return true
+		}
+
+		changes = append(changes, c)
+		return true
+	})
+
+	return changes
+}
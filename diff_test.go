@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+func TestDB_Diff(t *testing.T) {
+	a := assert.New(t, false)
+
+	db := newNamesDB(a)
+	a.NotError(db.AddItem("330322000000", "洞头县", 2018)).
+		NotError(db.AddItem("330306000000", "龙湾区", 2020))
+
+	changes := db.Diff(2018, 2020)
+	a.Length(changes, 3).
+		Equal(changes[0].Type, Renamed).
+		Equal(changes[0].FullID, "330305000000").
+		Equal(changes[0].Level, id.County).
+		Equal(changes[0].From, "洞头县").
+		Equal(changes[0].To, "洞头区").
+		Equal(changes[0].Region, db.Find("330305000000")).
+		Equal(changes[1].Type, Removed).
+		Equal(changes[1].FullID, "330322000000").
+		Equal(changes[1].To, "").
+		Equal(changes[2].Type, Added).
+		Equal(changes[2].FullID, "330306000000").
+		Equal(changes[2].From, "")
+
+	changes = db.Diff(2018, 2019)
+	a.Length(changes, 2).
+		Equal(changes[0].Type, Renamed).
+		Equal(changes[0].FullID, "330300000000").
+		Equal(changes[1].Type, Removed)
+
+	a.Empty(db.Diff(2020, 2020))
+
+	// 不存在的年份
+	changes = db.Diff(2001, 2020)
+	a.Length(changes, 4).Equal(changes[0].Type, Added)
+}
+
+func TestChangeType_String(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(Added.String(), "added").
+		Equal(Removed.String(), "removed").
+		Equal(Renamed.String(), "renamed").
+		Equal(ChangeType(0).String(), "unknown")
+}
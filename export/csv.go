@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/issue9/cnregion/v2"
+)
+
+// CSV 以扁平的 CSV 格式导出
+//
+// 第一行为标题，各列分别为：
+//
+//	full_id,name,full_name,level,parent_id,years
+//
+// level 为区域的层级，省级为 1，村级为 5；parent_id 为上一级区域的 ID，省级区域为空；
+// years 为支持的年份，以逗号分隔。
+func CSV(w io.Writer, db *cnregion.DB, o *Options) error {
+	o = o.sanitize()
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+
+	err := o.walk(db.Provinces(), func(r *row) error {
+		return cw.Write([]string{r.region.FullID(), r.name, r.full, strconv.Itoa(r.level), r.parent, r.years})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+var columns = []string{"full_id", "name", "full_name", "level", "parent_id", "years"}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestCSV(t *testing.T) {
+	a := assert.New(t, false)
+	db := newDB(a)
+
+	buf := new(bytes.Buffer)
+	a.NotError(CSV(buf, db, nil))
+	a.Equal(buf.String(), `full_id,name,full_name,level,parent_id,years
+330000000000,浙江省,浙江省,1,,"2020,2019"
+330300000000,温州市,浙江省-温州市,2,330000000000,"2020,2019"
+330305000000,洞头区,浙江省-温州市-洞头区,3,330300000000,"2020,2019"
+340000000000,安徽'省,安徽'省,1,,2020
+`)
+
+	buf.Reset()
+	a.NotError(CSV(buf, db, &Options{Year: 2019}))
+	a.Equal(buf.String(), `full_id,name,full_name,level,parent_id,years
+330000000000,浙江省,浙江省,1,,"2020,2019"
+330300000000,温州地区,浙江省-温州地区,2,330000000000,"2020,2019"
+330305000000,洞头县,浙江省-温州地区-洞头县,3,330300000000,"2020,2019"
+`)
+}
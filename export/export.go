@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+// Package export 将区域数据导出为其它格式
+//
+// 支持嵌套的 JSON、扁平的 CSV 以及 SQL 语句等格式。
+package export
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/issue9/cnregion/v2"
+	"github.com/issue9/cnregion/v2/id"
+)
+
+// Options 导出的选项
+type Options struct {
+	// 仅导出该年份的数据
+	//
+	// 名称也将采用该年份中的名称。0 表示导出所有年份的数据。
+	Year int
+
+	// 仅导出该级别及以上级别的数据
+	//
+	// 0 表示不限制。
+	MaxLevel id.Level
+
+	// SQL 语句中的表名
+	//
+	// 仅对 [SQL] 和 [Copy] 有效，为空表示 regions。
+	Table string
+}
+
+// 导出的单条记录
+type row struct {
+	region *cnregion.Region
+	name   string
+	full   string
+	level  int
+	parent string
+	years  string
+}
+
+func (o *Options) sanitize() *Options {
+	opt := &Options{}
+	if o != nil {
+		*opt = *o
+	}
+
+	if opt.Table == "" {
+		opt.Table = "regions"
+	}
+	return opt
+}
+
+// 返回 r 中需要导出的子项
+func (o *Options) items(r []*cnregion.Region) []*cnregion.Region {
+	list := make([]*cnregion.Region, 0, len(r))
+	for _, item := range r {
+		if (o.Year == 0 || item.IsSupported(o.Year)) && (o.MaxLevel == 0 || item.Level() >= o.MaxLevel) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// 以深度优先的顺序遍历所有需要导出的区域
+func (o *Options) walk(items []*cnregion.Region, fn func(*row) error) error {
+	for _, item := range o.items(items) {
+		if err := fn(o.row(item)); err != nil {
+			return err
+		}
+
+		if err := o.walk(item.Items(), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Options) row(r *cnregion.Region) *row {
+	name, full := r.Name(), r.FullName()
+	if o.Year > 0 {
+		name, full = r.NameAt(o.Year), r.FullNameAt(o.Year)
+	}
+
+	var parent string
+	if p := r.Parent(); p != nil {
+		parent = p.FullID()
+	}
+
+	years := make([]string, 0, len(r.Versions()))
+	for _, v := range r.Versions() {
+		years = append(years, strconv.Itoa(v))
+	}
+
+	return &row{
+		region: r,
+		name:   name,
+		full:   full,
+		level:  depth(r.Level()),
+		parent: parent,
+		years:  strings.Join(years, ","),
+	}
+}
+
+// 将 [id.Level] 转换为层级，省级为 1，村级为 5。
+func depth(level id.Level) int {
+	switch level {
+	case id.Province:
+		return 1
+	case id.City:
+		return 2
+	case id.County:
+		return 3
+	case id.Town:
+		return 4
+	case id.Village:
+		return 5
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/cnregion/v2"
+	"github.com/issue9/cnregion/v2/id"
+)
+
+var data = []byte(`3:[2020,2019]:::3:2{33:浙江省:3:1{03:温州市@1;温州地区@2:3:1{05:洞头区@1;洞头县@2:3:0{}}}34:安徽'省:1:0{}}`)
+
+func newDB(a *assert.Assertion) *cnregion.DB {
+	db, err := cnregion.Load(data, "-", false)
+	a.NotError(err).NotNil(db)
+	return db
+}
+
+func TestOptions_sanitize(t *testing.T) {
+	a := assert.New(t, false)
+
+	var o *Options
+	a.Equal(o.sanitize(), &Options{Table: "regions"})
+
+	o = &Options{Year: 2020}
+	a.Equal(o.sanitize(), &Options{Year: 2020, Table: "regions"}).
+		Equal(o.Table, "")
+}
+
+func TestDepth(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(depth(id.Province), 1).
+		Equal(depth(id.Village), 5).
+		Equal(depth(0), 0)
+}
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/issue9/cnregion/v2"
+)
+
+// 级联选择器的节点
+type node struct {
+	Value    string  `json:"value"`
+	Label    string  `json:"label"`
+	Children []*node `json:"children,omitempty"`
+}
+
+// JSON 以嵌套的 JSON 格式导出
+//
+// 输出的格式为大部分前端级联选择器所采用的格式：
+//
+//	[{"value": "330000000000", "label": "浙江省", "children": [...]}]
+func JSON(w io.Writer, db *cnregion.DB, o *Options) error {
+	o = o.sanitize()
+	return json.NewEncoder(w).Encode(o.nodes(db.Provinces()))
+}
+
+func (o *Options) nodes(items []*cnregion.Region) []*node {
+	items = o.items(items)
+	nodes := make([]*node, 0, len(items))
+	for _, item := range items {
+		r := o.row(item)
+		nodes = append(nodes, &node{
+			Value:    item.FullID(),
+			Label:    r.name,
+			Children: o.nodes(item.Items()),
+		})
+	}
+	return nodes
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+func TestJSON(t *testing.T) {
+	a := assert.New(t, false)
+	db := newDB(a)
+
+	buf := new(bytes.Buffer)
+	a.NotError(JSON(buf, db, nil))
+	a.Equal(buf.String(), `[{"value":"330000000000","label":"浙江省","children":[{"value":"330300000000","label":"温州市","children":[{"value":"330305000000","label":"洞头区"}]}]},{"value":"340000000000","label":"安徽'省"}]`+"\n")
+
+	buf.Reset()
+	a.NotError(JSON(buf, db, &Options{Year: 2019, MaxLevel: id.City}))
+	a.Equal(buf.String(), `[{"value":"330000000000","label":"浙江省","children":[{"value":"330300000000","label":"温州地区"}]}]`+"\n")
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/issue9/cnregion/v2"
+)
+
+// Schema 输出创建数据表的语句
+//
+// 表结构与 [SQL] 和 [Copy] 导出的数据相对应。
+func Schema(w io.Writer, o *Options) error {
+	o = o.sanitize()
+	_, err := fmt.Fprintf(w, `CREATE TABLE %s (
+	full_id CHAR(12) NOT NULL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	full_name VARCHAR(500) NOT NULL,
+	level SMALLINT NOT NULL,
+	parent_id CHAR(12),
+	years VARCHAR(200) NOT NULL
+);
+`, o.Table)
+	return err
+}
+
+// SQL 以 INSERT 语句的形式导出
+//
+// 每个区域一条语句，省级区域的 parent_id 为 NULL。
+func SQL(w io.Writer, db *cnregion.DB, o *Options) error {
+	o = o.sanitize()
+	bw := bufio.NewWriter(w)
+	cols := strings.Join(columns, ", ")
+
+	err := o.walk(db.Provinces(), func(r *row) error {
+		parent := "NULL"
+		if r.parent != "" {
+			parent = quote(r.parent)
+		}
+
+		_, err := fmt.Fprintf(bw, "INSERT INTO %s (%s) VALUES (%s, %s, %s, %d, %s, %s);\n",
+			o.Table, cols, quote(r.region.FullID()), quote(r.name), quote(r.full), r.level, parent, quote(r.years))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// Copy 以 PostgreSQL 的 COPY 语句的形式导出
+func Copy(w io.Writer, db *cnregion.DB, o *Options) error {
+	o = o.sanitize()
+	bw := bufio.NewWriter(w)
+
+	if _, err := fmt.Fprintf(bw, "COPY %s (%s) FROM stdin;\n", o.Table, strings.Join(columns, ", ")); err != nil {
+		return err
+	}
+
+	err := o.walk(db.Provinces(), func(r *row) error {
+		parent := `\N`
+		if r.parent != "" {
+			parent = r.parent
+		}
+
+		_, err := fmt.Fprintf(bw, "%s\t%s\t%s\t%d\t%s\t%s\n",
+			r.region.FullID(), copyEscape(r.name), copyEscape(r.full), r.level, parent, r.years)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := bw.WriteString("\\.\n"); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// 转换为 SQL 中的字符串
+func quote(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+
+var copyReplacer = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// 转义 COPY 语句中的文本
+func copyEscape(s string) string { return copyReplacer.Replace(s) }
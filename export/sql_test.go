@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+func TestSchema(t *testing.T) {
+	a := assert.New(t, false)
+
+	buf := new(bytes.Buffer)
+	a.NotError(Schema(buf, &Options{Table: "t1"}))
+	a.Contains(buf.String(), "CREATE TABLE t1 (")
+}
+
+func TestSQL(t *testing.T) {
+	a := assert.New(t, false)
+	db := newDB(a)
+
+	buf := new(bytes.Buffer)
+	a.NotError(SQL(buf, db, &Options{MaxLevel: id.City}))
+	a.Equal(buf.String(), `INSERT INTO regions (full_id, name, full_name, level, parent_id, years) VALUES ('330000000000', '浙江省', '浙江省', 1, NULL, '2020,2019');
+INSERT INTO regions (full_id, name, full_name, level, parent_id, years) VALUES ('330300000000', '温州市', '浙江省-温州市', 2, '330000000000', '2020,2019');
+INSERT INTO regions (full_id, name, full_name, level, parent_id, years) VALUES ('340000000000', '安徽''省', '安徽''省', 1, NULL, '2020');
+`)
+}
+
+func TestCopy(t *testing.T) {
+	a := assert.New(t, false)
+	db := newDB(a)
+
+	buf := new(bytes.Buffer)
+	a.NotError(Copy(buf, db, &Options{Year: 2020, MaxLevel: id.Province, Table: "t1"}))
+	a.Equal(buf.String(), "COPY t1 (full_id, name, full_name, level, parent_id, years) FROM stdin;\n"+
+		"330000000000\t浙江省\t浙江省\t1\t\\N\t2020,2019\n"+
+		"340000000000\t安徽'省\t安徽'省\t1\t\\N\t2020\n"+
+		"\\.\n")
+
+	a.Equal(copyEscape("a\tb\\c\nd"), `a\tb\\c\nd`)
+}
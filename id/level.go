@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package id
+
+import (
+	"encoding"
+	"fmt"
+	"strings"
+)
+
+var (
+	_ fmt.Stringer             = Level(0)
+	_ encoding.TextMarshaler   = Level(0)
+	_ encoding.TextUnmarshaler = (*Level)(nil)
+)
+
+// 按由高到低的顺序排列的级别名称
+var levelNames = []struct {
+	level Level
+	name  string
+}{
+	{Province, "province"},
+	{City, "city"},
+	{County, "county"},
+	{Town, "town"},
+	{Village, "village"},
+}
+
+const allLevelName = "all"
+
+// String 返回级别的名称
+//
+// 多个级别的组合以 | 分隔，比如 province|city，[AllLevel] 返回 all。
+// 包含无效值时返回数字形式。
+func (l Level) String() string {
+	if s, err := l.marshal(); err == nil {
+		return s
+	}
+	return fmt.Sprintf("Level(%d)", uint8(l))
+}
+
+// MarshalText 实现 [encoding.TextMarshaler] 接口
+func (l Level) MarshalText() ([]byte, error) {
+	s, err := l.marshal()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText 实现 [encoding.TextUnmarshaler] 接口
+//
+// 可以是 province、city、county、town、village 和 all 以及以 | 分隔的组合。
+func (l *Level) UnmarshalText(data []byte) error {
+	v, err := ParseLevel(string(data))
+	if err != nil {
+		return err
+	}
+	*l = v
+	return nil
+}
+
+// ParseLevel 将名称转换为 [Level]
+//
+// 格式与 [Level.UnmarshalText] 相同，空字符串返回 0。
+func ParseLevel(s string) (Level, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	var l Level
+LOOP:
+	for _, name := range strings.Split(s, "|") {
+		name = strings.TrimSpace(name)
+		if name == allLevelName {
+			l |= AllLevel
+			continue
+		}
+
+		for _, item := range levelNames {
+			if item.name == name {
+				l |= item.level
+				continue LOOP
+			}
+		}
+		return 0, fmt.Errorf("无效的区域级别 %s", name)
+	}
+	return l, nil
+}
+
+func (l Level) marshal() (string, error) {
+	switch {
+	case l == AllLevel:
+		return allLevelName, nil
+	case l&^AllLevel != 0:
+		return "", fmt.Errorf("无效的区域级别 %d", uint8(l))
+	}
+
+	names := make([]string, 0, len(levelNames))
+	for _, item := range levelNames {
+		if l&item.level == item.level {
+			names = append(names, item.name)
+		}
+	}
+	return strings.Join(names, "|"), nil
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package id
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestLevel_String(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(Province.String(), "province").
+		Equal(Village.String(), "village").
+		Equal((Province|City).String(), "province|city").
+		Equal((Village|Province).String(), "province|village").
+		Equal(AllLevel.String(), "all").
+		Equal(Level(0).String(), "").
+		Equal(Level(32).String(), "Level(32)")
+}
+
+func TestParseLevel(t *testing.T) {
+	a := assert.New(t, false)
+
+	l, err := ParseLevel("city")
+	a.NotError(err).Equal(l, City)
+
+	l, err = ParseLevel("village | province")
+	a.NotError(err).Equal(l, Village|Province)
+
+	l, err = ParseLevel("all")
+	a.NotError(err).Equal(l, AllLevel)
+
+	l, err = ParseLevel("")
+	a.NotError(err).Equal(l, 0)
+
+	l, err = ParseLevel("city|state")
+	a.Error(err).Equal(l, 0)
+}
+
+func TestLevel_JSON(t *testing.T) {
+	a := assert.New(t, false)
+
+	type obj struct {
+		Level  Level   `json:"level"`
+		Levels []Level `json:"levels"`
+	}
+
+	data, err := json.Marshal(&obj{Level: County, Levels: []Level{Town, City | Town}})
+	a.NotError(err).Equal(string(data), `{"level":"county","levels":["town","city|town"]}`)
+
+	o := &obj{}
+	a.NotError(json.Unmarshal(data, o))
+	a.Equal(o.Level, County).Equal(o.Levels, []Level{Town, City | Town})
+
+	a.Error(json.Unmarshal([]byte(`{"level":"state"}`), o))
+
+	_, err = json.Marshal(&obj{Level: 64})
+	a.Error(err)
+}
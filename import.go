@@ -0,0 +1,296 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+// ImportFormat 导入数据的格式
+type ImportFormat int8
+
+// 支持的导入格式
+//
+// 每一行数据包含 id、name 和可选的 year 三个字段：
+//   - id 为区域的 ID，可以是完整的 12 位 ID，也可以是去掉后缀 0 的 2、4、6 和 9 位 ID；
+//   - name 为区域的名称；
+//   - year 为该行数据对应的年份，如果为空，则采用 [Import] 的 year 参数；
+//
+// TSV 和 CSV 中以 # 开头的行会被忽略，第一行如果是以 id 开头的表头也会被忽略；
+// JSON 为对象数组，对象的字段名分别为 id、name 和 year。
+const (
+	ImportTSV ImportFormat = iota
+	ImportCSV
+	ImportJSON
+)
+
+// ImportError 导入数据时的错误
+type ImportError struct {
+	Line int    // 出错的行号，从 1 开始。
+	ID   string // 出错行的区域 ID，可以为空。
+	Err  error
+}
+
+func (e *ImportError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("第 %d 行：%s", e.Line, e.Err)
+	}
+	return fmt.Sprintf("第 %d 行，区域 %s：%s", e.Line, e.ID, e.Err)
+}
+
+func (e *ImportError) Unwrap() error { return e.Err }
+
+// 导入数据中的一行
+type importRow struct {
+	line int
+	id   string
+	name string
+	year string
+}
+
+// Import 从 r 中导入数据并生成新的 [DB] 对象
+//
+// year 为数据的默认年份，当行数据中未指定年份时采用此值；
+// format 为数据的格式；
+// opts 中仅 [WithSeparator]、[WithPlaceholder] 和 [WithSearchIndex] 有效，
+// 指定 [WithPlaceholder] 时，不再要求上级区域必须在子区域之前导入。
+//
+// 所有行的错误都会以 [ImportError] 的形式通过 [errors.Join] 合并返回，
+// 而不是在第一个错误时就中止。
+func Import(r io.Reader, year int, format ImportFormat, opts ...Option) (*DB, error) {
+	db := NewDB(opts...)
+	if err := db.Import(r, year, format); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Import 从 r 中导入数据至当前对象
+//
+// 参数与 [Import] 函数相同。上级区域必须在子区域之前导入，
+// 可以是 db 中已经存在的，也可以是 r 中前面的行。
+//
+// 出错时，db 中可能已经包含了部分正确的数据。
+func (db *DB) Import(r io.Reader, year int, format ImportFormat) error {
+	var rows []*importRow
+	var errs []*ImportError
+	var err error
+	switch format {
+	case ImportTSV:
+		rows, errs, err = readTSV(r)
+	case ImportCSV:
+		rows, errs, err = readCSV(r)
+	case ImportJSON:
+		rows, errs, err = readJSON(r)
+	default:
+		return fmt.Errorf("不支持的导入格式 %d", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	type key struct {
+		id   string
+		year int
+	}
+	seen := make(map[key]int, len(rows))
+
+	for _, row := range rows {
+		fullID, y, err := db.importRow(row, year)
+		if err != nil {
+			errs = append(errs, &ImportError{Line: row.line, ID: row.id, Err: err})
+			continue
+		}
+
+		k := key{id: fullID, year: y}
+		if line, found := seen[k]; found {
+			errs = append(errs, &ImportError{Line: row.line, ID: row.id, Err: fmt.Errorf("与第 %d 行重复", line)})
+			continue
+		}
+		seen[k] = row.line
+	}
+
+	slices.SortStableFunc(errs, func(a, b *ImportError) int { return cmp.Compare(a.Line, b.Line) })
+	list := make([]error, 0, len(errs))
+	for _, e := range errs {
+		list = append(list, e)
+	}
+	return errors.Join(list...)
+}
+
+// 导入单行数据，返回完整的 ID 和年份。
+func (db *DB) importRow(row *importRow, year int) (string, int, error) {
+	if row.year != "" {
+		y, err := strconv.Atoi(row.year)
+		if err != nil || y <= 0 {
+			return "", 0, fmt.Errorf("无效的年份 %s", row.year)
+		}
+		year = y
+	}
+	if year <= 0 {
+		return "", 0, errors.New("未指定年份")
+	}
+
+	if row.name == "" {
+		return "", 0, errors.New("名称不能为空")
+	}
+
+	fullID, err := normalizeID(row.id)
+	if err != nil {
+		return "", 0, err
+	}
+
+	list := id.SplitFilter(fullID)
+	parent := db.root.findItem(list[:len(list)-1]...)
+	if parent != nil && !parent.placeholder && !parent.isSupportedAt(year) {
+		return "", 0, fmt.Errorf("上级区域 %s 在 %d 年份中不存在", parent.fullID, year)
+	}
+
+	added := db.AddVersion(year)
+	if err := db.AddItem(fullID, row.name, year); err != nil {
+		if added { // 不保留没有任何数据的年份
+			db.RemoveVersion(year)
+		}
+		return "", 0, err
+	}
+	return fullID, year, nil
+}
+
+// 根据字段生成 importRow，header 表示是否可能为表头。
+func newImportRow(line int, fields []string, header bool) (*importRow, *ImportError) {
+	if header && len(fields) > 0 && strings.EqualFold(strings.TrimSpace(fields[0]), "id") {
+		return nil, nil
+	}
+
+	if len(fields) != 2 && len(fields) != 3 {
+		return nil, &ImportError{Line: line, Err: fmt.Errorf("字段数量必须为 2 或 3，当前为 %d", len(fields))}
+	}
+
+	row := &importRow{
+		line: line,
+		id:   strings.TrimSpace(fields[0]),
+		name: strings.TrimSpace(fields[1]),
+	}
+	if len(fields) == 3 {
+		row.year = strings.TrimSpace(fields[2])
+	}
+	return row, nil
+}
+
+// 读取数据，返回正确的行和各行的错误信息，error 表示无法继续读取的错误。
+func readTSV(r io.Reader) (rows []*importRow, errs []*ImportError, err error) {
+	s := bufio.NewScanner(r)
+	first := true
+	for line := 1; s.Scan(); line++ {
+		txt := strings.TrimRightFunc(s.Text(), unicode.IsSpace)
+		if strings.TrimSpace(txt) == "" || txt[0] == '#' {
+			continue
+		}
+
+		row, ie := newImportRow(line, strings.Split(txt, "\t"), first)
+		first = false
+		if ie != nil {
+			errs = append(errs, ie)
+		} else if row != nil {
+			rows = append(rows, row)
+		}
+	}
+	return rows, errs, s.Err()
+}
+
+func readCSV(r io.Reader) (rows []*importRow, errs []*ImportError, err error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	first := true
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var pe *csv.ParseError
+		if errors.As(err, &pe) {
+			errs = append(errs, &ImportError{Line: pe.Line, Err: pe.Err})
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		row, ie := newImportRow(line, record, first)
+		first = false
+		if ie != nil {
+			errs = append(errs, ie)
+		} else if row != nil {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, errs, nil
+}
+
+func readJSON(r io.Reader) (rows []*importRow, errs []*ImportError, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lineAt := func(offset int64) int { return bytes.Count(data[:offset], []byte{'\n'}) + 1 }
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	if t, err := d.Token(); err != nil {
+		return nil, []*ImportError{{Line: lineAt(d.InputOffset()), Err: err}}, nil
+	} else if t != json.Delim('[') {
+		return nil, []*ImportError{{Line: lineAt(d.InputOffset()), Err: errors.New("必须为数组")}}, nil
+	}
+
+	type object struct {
+		ID   string      `json:"id"`
+		Name string      `json:"name"`
+		Year json.Number `json:"year"`
+	}
+
+	for d.More() {
+		// 跳过上一个元素之后的空白和逗号，以获得当前元素的起始行号。
+		offset := d.InputOffset()
+		for int(offset) < len(data) && (data[offset] == ',' || unicode.IsSpace(rune(data[offset]))) {
+			offset++
+		}
+		line := lineAt(offset)
+
+		obj := &object{}
+		if err := d.Decode(obj); err != nil {
+			var te *json.UnmarshalTypeError
+			errs = append(errs, &ImportError{Line: line, Err: err})
+			if !errors.As(err, &te) { // 语法错误无法继续解析
+				return rows, errs, nil
+			}
+			continue
+		}
+
+		rows = append(rows, &importRow{line: line, id: obj.ID, name: obj.Name, year: obj.Year.String()})
+	}
+
+	if _, err := d.Token(); err != nil {
+		errs = append(errs, &ImportError{Line: lineAt(d.InputOffset()), Err: err})
+	}
+
+	return rows, errs, nil
+}
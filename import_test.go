@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestImport(t *testing.T) {
+	a := assert.New(t, false)
+
+	tsv := "id\tname\tyear\n" +
+		"# 注释\n" +
+		"33\t浙江\n" +
+		"33\t浙江\t2019\n" +
+		"330300000000\t温州\n" +
+		"3303\t温州\t2019\n" +
+		"330305\t洞头县\t2019\n" +
+		"330305\t洞头区\n" +
+		"34\t安徽\t2019\n"
+	db, err := Import(strings.NewReader(tsv), 2020, ImportTSV, WithSeparator("-"))
+	a.NotError(err).NotNil(db).
+		Equal(db.Versions(), []int{2020, 2019})
+
+	r := db.Find("330305000000")
+	a.NotNil(r).
+		Equal(r.Name(), "洞头区").
+		Equal(r.FullName(), "浙江-温州-洞头区").
+		Equal(r.NameAt(2019), "洞头县").
+		Equal(r.Versions(), []int{2019, 2020})
+	a.Equal(db.Find("340000000000").Versions(), []int{2019})
+
+	csv := "33,浙江,2020\n\"3303\",\"温州\",2020\n"
+	db, err = Import(strings.NewReader(csv), 0, ImportCSV)
+	a.NotError(err).NotNil(db).
+		Equal(db.Find("330300000000").FullName(), "浙江温州")
+
+	json := `[
+	{"id":"33","name":"浙江"},
+	{"id":"3303","name":"温州","year":2019}
+]`
+	db, err = Import(strings.NewReader(json), 2019, ImportJSON)
+	a.NotError(err).NotNil(db).
+		Equal(db.Find("330300000000").Name(), "温州")
+
+	_, err = Import(strings.NewReader(""), 2020, ImportFormat(10))
+	a.Error(err)
+}
+
+func TestImport_errors(t *testing.T) {
+	a := assert.New(t, false)
+
+	tsv := "33\t浙江\n" +
+		"3303\t温州\n" +
+		"330305\n" + // 字段数量不对
+		"3401\t合肥\n" + // 上级不存在
+		"33a3\t温州\n" + // 无效的字符
+		"3304\t\n" + // 名称为空
+		"3303\t温州\n" + // 重复
+		"3305\t湖州\tabc\n" + // 无效的年份
+		"330006\t绍兴\n" + // 无效的 ID
+		"330302\t鹿城\t2019\n" // 上级在该年份中不存在
+	db, err := Import(strings.NewReader(tsv), 2020, ImportTSV)
+	a.Error(err).Nil(db)
+
+	var lines []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		ie := &ImportError{}
+		a.True(errors.As(e, &ie))
+		lines = append(lines, ie.Line)
+	}
+	a.Equal(lines, []int{3, 4, 5, 6, 7, 8, 9, 10})
+
+	// 未指定年份
+	_, err = Import(strings.NewReader("33\t浙江\n"), 0, ImportTSV)
+	a.ErrorString(err, "第 1 行，区域 33：未指定年份")
+
+	// CSV
+	csv := "33,浙江\n3303,\"温州\n"
+	_, err = Import(strings.NewReader(csv), 2020, ImportCSV)
+	a.Error(err)
+	ie := &ImportError{}
+	a.True(errors.As(err, &ie)).Equal(ie.Line, 2)
+
+	// JSON
+	json := `[
+	{"id":"33","name":"浙江"},
+	{"id":3303,"name":"温州"},
+	{"id":"3401","name":"合肥"}
+]`
+	_, err = Import(strings.NewReader(json), 2020, ImportJSON)
+	lines = lines[:0]
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		ie := &ImportError{}
+		a.True(errors.As(e, &ie))
+		lines = append(lines, ie.Line)
+	}
+	a.Equal(lines, []int{3, 4})
+
+	_, err = Import(strings.NewReader(`{"id":"33"}`), 2020, ImportJSON)
+	a.ErrorString(err, "必须为数组")
+
+	_, err = Import(strings.NewReader(`[{"id":"33","name":"浙江"},{`), 2020, ImportJSON)
+	a.True(errors.As(err, &ie)).Equal(ie.Line, 1)
+}
+
+func TestImport_placeholder(t *testing.T) {
+	a := assert.New(t, false)
+
+	tsv := "330305\t洞头\n3303\t温州\n33\t浙江\n"
+	_, err := Import(strings.NewReader(tsv), 2020, ImportTSV)
+	a.ErrorIs(err, ErrParentNotFound)
+
+	db, err := Import(strings.NewReader(tsv), 2020, ImportTSV, WithSeparator("-"), WithPlaceholder(""))
+	a.NotError(err).NotNil(db).
+		Equal(db.Find("330305000000").FullName(), "浙江-温州-洞头")
+}
+
+func TestDB_Import(t *testing.T) {
+	a := assert.New(t, false)
+
+	db, err := Load(data, "-", false)
+	a.NotError(err).NotNil(db)
+
+	a.NotError(db.Import(strings.NewReader("3302\t宁波\n330201\t海曙\n"), 2020, ImportTSV))
+	a.Equal(db.Find("330201000000").FullName(), "浙江-宁波-海曙")
+
+	// 33 仅支持 2020
+	a.Error(db.Import(strings.NewReader("3303\t宁波\n"), 2019, ImportTSV))
+
+	// 新的省份
+	a.NotError(db.Import(strings.NewReader("35\t福建\n"), 2020, ImportTSV))
+	var provinces []string
+	for _, d := range db.Districts() {
+		for _, p := range d.Items() {
+			provinces = append(provinces, p.FullID())
+		}
+	}
+	a.Length(provinces, 3).Contains(provinces, "350000000000")
+
+	// 导入失败时不会添加新的年份
+	a.ErrorIs(db.Import(strings.NewReader("3601\t南昌\t2018\n"), 2020, ImportTSV), ErrParentNotFound).
+		Equal(db.Versions(), []int{2020, 2019})
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+// 区域名称的倒排索引
+//
+// 以名称中的每一个字符为键，值为名称中包含该字符的区域列表，
+// 名称包含历史记录中的所有名称，列表按深度优先的顺序排列，与遍历的顺序相同。
+type searchIndex struct {
+	once  sync.Once
+	runes map[rune][]*Region
+}
+
+// WithSearchIndex 为 [DB.Search] 建立名称的索引
+//
+// 加载时会为所有区域的名称建立索引，之后以 [Options.Text] 搜索时，
+// 仅需要检查索引中的区域，而不是遍历所有的区域。
+// 索引会占用额外的内存，对于二进制格式的数据，也会在加载时解码所有的子项。
+func WithSearchIndex() Option { return func(o *options) { o.index = true } }
+
+// 初始化索引
+//
+// 在加载数据之后调用。
+func (db *DB) initIndex(enable bool) {
+	if enable {
+		db.index = &searchIndex{}
+		db.index.get(db)
+	}
+}
+
+// 数据被修改之后需要重建索引
+//
+// 索引会在下一次搜索时重新生成。
+func (db *DB) resetIndex() {
+	if db.index != nil {
+		db.index = &searchIndex{}
+	}
+}
+
+func (idx *searchIndex) get(db *DB) map[rune][]*Region {
+	idx.once.Do(func() {
+		idx.runes = make(map[rune][]*Region, 5000)
+		db.root.walk(id.AllLevel, func(r *Region) bool {
+			var names string
+			for _, n := range r.NameHistory() {
+				names += n.Name
+			}
+
+			for i, c := range names {
+				if strings.IndexRune(names[:i], c) > -1 { // 同一区域中重复的字符只记录一次
+					continue
+				}
+				idx.runes[c] = append(idx.runes[c], r)
+			}
+			return true
+		})
+	})
+	return idx.runes
+}
+
+// 通过索引搜索
+//
+// r 为搜索的起点，即 [Options.Parent] 对应的区域。
+func (idx *searchIndex) search(db *DB, r *Region, s *searcher) {
+	runes := idx.get(db)
+
+	var candidates []*Region
+	for i, c := range s.text { // 以包含区域最少的字符作为候选列表
+		list := runes[c]
+		if i == 0 || len(list) < len(candidates) {
+			candidates = list
+		}
+		if len(candidates) == 0 {
+			return
+		}
+	}
+
+	prefix := ""
+	if r.level != 0 {
+		prefix = id.Prefix(r.fullID)
+	}
+
+	for _, reg := range candidates {
+		if !s.unlimited && s.max <= 0 {
+			return
+		}
+
+		if reg.level&s.level != reg.level ||
+			!strings.HasPrefix(reg.fullID, prefix) ||
+			!strings.Contains(reg.searchName(s.year), s.text) ||
+			!isPathSupportedAt(reg, r, s.year) {
+			continue
+		}
+
+		s.list = append(s.list, reg)
+		s.max--
+	}
+}
+
+// 从 reg 到 root 之间的所有区域是否都支持 year 年份
+//
+// 与遍历时的行为保持一致，root 的上级区域不作检测。
+func isPathSupportedAt(reg, root *Region, year int) bool {
+	for p := reg; p != nil; p = p.parent {
+		if !p.isSupportedAt(year) {
+			return false
+		}
+		if p == root {
+			break
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+func TestSearchIndex(t *testing.T) {
+	a := assert.New(t, false)
+
+	scan, err := Load(data, "-", false)
+	a.NotError(err).NotNil(scan).Nil(scan.index)
+	indexed, err := LoadReader(bytes.NewReader(data), WithSeparator("-"), WithSearchIndex())
+	a.NotError(err).NotNil(indexed).NotNil(indexed.index)
+
+	opts := []*Options{
+		{Text: "合肥"},
+		{Text: "芜湖"},
+		{Text: "湖芜"},
+		{Text: "安"},
+		{Text: "不存在"},
+		{Text: "湖", Max: 1},
+		{Text: "湖", Level: id.Province},
+		{Text: "湖", Parent: "340000000000"},
+		{Text: "湖", Parent: "330000000000"},
+		{Text: "温", Parent: "330100000000"},
+		{Text: "湖", Parent: "000000000000"},
+		{Level: id.City},
+		{Text: "wuhu", Mode: MatchPinyin},
+		{Text: "安徽合肥市", Normalize: true},
+	}
+	for _, opt := range opts {
+		a.Equal(names(indexed.Search(opt)), names(scan.Search(opt)), "%+v", opt)
+		a.Equal(names(indexed.At(2019).Search(opt)), names(scan.At(2019).Search(opt)), "%+v", opt)
+	}
+}
+
+func TestSearchIndex_reset(t *testing.T) {
+	a := assert.New(t, false)
+
+	db := NewDB(WithSeparator("-"), WithSearchIndex())
+	a.NotNil(db.index).True(db.AddVersion(2020))
+	a.Empty(db.Search(&Options{Text: "温州"}))
+
+	a.NotError(db.AddItem("33", "浙江", 2020)).
+		NotError(db.AddItem("3303", "温州", 2020))
+	a.Equal(names(db.Search(&Options{Text: "温州"})), []string{"浙江-温州"})
+
+	a.NotError(db.Rename("330300000000", "温州市", 2020))
+	a.Equal(names(db.Search(&Options{Text: "州市"})), []string{"浙江-温州市"})
+
+	a.NotError(db.RemoveItem("330300000000", 0))
+	a.Empty(db.Search(&Options{Text: "温州"}))
+}
+
+func names(list []*Region) []string {
+	n := make([]string, 0, len(list))
+	for _, r := range list {
+		n = append(n, r.FullName())
+	}
+	return n
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+// MergeConflict 合并数据时同一区域在同一年份中的名称冲突
+type MergeConflict struct {
+	FullID string
+	Year   int
+	Name   string // 当前对象中的名称，合并之后保留此名称。
+	Other  string // 被合并对象中的名称
+}
+
+func (c *MergeConflict) Error() string {
+	return fmt.Sprintf("%s 在 %d 年份中的名称冲突：%s 和 %s", c.FullID, c.Year, c.Name, c.Other)
+}
+
+// Merge 将 other 中的数据合并到当前对象
+//
+// 合并年份列表、区域以及继任关系。对于两者都存在的区域，
+// 如果在同一年份中的名称不同，则保留当前对象中的名称，
+// 并以 [MergeConflict] 的形式通过 [errors.Join] 合并返回，
+// 即使返回了错误，其它数据也已经完成合并。
+func (db *DB) Merge(other *DB) error {
+	for _, v := range other.versions {
+		db.AddVersion(v)
+	}
+
+	var errs []error
+	other.root.walk(id.AllLevel, func(r *Region) bool {
+		exist := db.Find(r.fullID)
+		for _, v := range r.versions {
+			name := r.NameAt(v)
+
+			if exist != nil && exist.IsSupported(v) {
+				if n := exist.NameAt(v); n != name {
+					errs = append(errs, &MergeConflict{FullID: r.fullID, Year: v, Name: n, Other: name})
+				}
+				continue
+			}
+
+			if err := db.AddItem(r.fullID, name, v); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return true
+	})
+
+	for from, list := range other.successors {
+		for _, s := range list {
+			if err := db.AddSuccessor(from, s.to, s.year); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if db.districts != nil {
+		db.initDistricts()
+	}
+
+	return errors.Join(errs...)
+}
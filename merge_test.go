@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestDB_Merge(t *testing.T) {
+	a := assert.New(t, false)
+
+	db1 := NewDB(WithSeparator("-"))
+	a.True(db1.AddVersion(2020))
+	a.NotError(db1.AddItem("33", "浙江省", 2020)).
+		NotError(db1.AddItem("3303", "温州市", 2020)).
+		NotError(db1.AddItem("330305", "洞头区", 2020))
+
+	db2 := NewDB()
+	a.True(db2.AddVersion(2019)).True(db2.AddVersion(2020))
+	a.NotError(db2.AddItem("33", "浙江省", 2019)).
+		NotError(db2.AddItem("3303", "温州地区", 2019)).
+		NotError(db2.AddItem("330322", "洞头县", 2019)).
+		NotError(db2.AddItem("34", "安徽省", 2019)).
+		NotError(db2.AddItem("33", "浙江省", 2020)).
+		NotError(db2.AddItem("3303", "温州", 2020)). // 与 db1 冲突
+		NotError(db2.AddSuccessor("330322000000", "330305000000", 2020))
+
+	err := db1.Merge(db2)
+	a.Error(err)
+	c := &MergeConflict{}
+	a.True(errors.As(err, &c)).
+		Equal(c, &MergeConflict{FullID: "330300000000", Year: 2020, Name: "温州市", Other: "温州"})
+
+	a.Equal(db1.Versions(), []int{2020, 2019})
+
+	r := db1.Find("330300000000")
+	a.NotNil(r).
+		Equal(r.Versions(), []int{2020, 2019}).
+		Equal(r.NameAt(2020), "温州市").
+		Equal(r.NameAt(2019), "温州地区")
+
+	r = db1.Find("330322000000")
+	a.NotNil(r).
+		Equal(r.FullNameAt(2019), "浙江省-温州地区-洞头县").
+		Equal(r.Versions(), []int{2019})
+
+	a.NotNil(db1.Find("340000000000")).
+		Equal(db1.Successors("330322000000", 2020), []*Region{db1.Find("330305000000")})
+
+	// 合并之后的数据可以正常编码
+	db3 := reloadDB(a, db1)
+	a.Equal(db3.Find("330300000000").NameAt(2019), "温州地区")
+
+	// 合并相同的数据
+	a.NotError(db3.Merge(reloadDB(a, db3)))
+}
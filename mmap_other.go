@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package cnregion
+
+import "os"
+
+// 不支持 mmap 的系统直接读取文件内容
+func mmapFile(file string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(file)
+	return data, nil, err
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package cnregion
+
+import (
+	"os"
+	"syscall"
+)
+
+// 以只读的方式将文件映射到内存
+//
+// 返回的函数用于释放映射的内存。
+func mmapFile(file string) ([]byte, func() error, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if stat.Size() == 0 { // 无法映射空文件
+		return []byte{}, nil, nil
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(stat.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+// ErrNotFound 区域不存在
+var ErrNotFound = errors.New("区域不存在")
+
+// RemoveItem 删除区域在 year 年份中的数据
+//
+// 子区域在该年份中的数据也会一并删除，如果删除之后区域不再支持任何年份，
+// 则从上一级区域中移除；year 为 0 表示直接删除该区域及其所有子区域。
+func (db *DB) RemoveItem(fullID string, year int) error {
+	reg, err := db.findRegion(fullID)
+	if err != nil {
+		return err
+	}
+
+	if year != 0 && !reg.IsSupported(year) {
+		return fmt.Errorf("%w：%s 不支持 %d", ErrUnknownVersion, fullID, year)
+	}
+
+	db.resetIndex()
+	if year == 0 || reg.removeVersion(year) {
+		reg.parent.items = slices.DeleteFunc(reg.parent.Items(), func(item *Region) bool { return item == reg })
+		if reg.level == id.Province && db.districts != nil {
+			db.initDistricts()
+		}
+	}
+	return nil
+}
+
+// RemoveVersion 删除 year 年份的数据
+//
+// 所有区域都会删除该年份，不再支持任何年份的区域会被移除。
+func (db *DB) RemoveVersion(year int) error {
+	i := slices.Index(db.versions, year)
+	if i == -1 {
+		return fmt.Errorf("%w：%d", ErrUnknownVersion, year)
+	}
+
+	db.resetIndex()
+	db.root.removeVersion(year)
+	db.versions = slices.Delete(db.versions, i, i+1)
+	if db.districts != nil {
+		db.initDistricts()
+	}
+	return nil
+}
+
+// Rename 修改区域在 year 年份中的名称
+//
+// year 为 0 表示修改所有年份中的名称，同时也会清除名称的历史记录。
+func (db *DB) Rename(fullID, name string, year int) error {
+	reg, err := db.findRegion(fullID)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		return errors.New("名称不能为空")
+	}
+
+	db.resetIndex()
+	if year == 0 {
+		reg.names = nil
+		reg.name = name
+		reg.updateFullName()
+		return nil
+	}
+
+	if !reg.IsSupported(year) {
+		return fmt.Errorf("%w：%s 不支持 %d", ErrUnknownVersion, fullID, year)
+	}
+
+	reg.resetNames(func(v int) string {
+		if v == year {
+			return name
+		}
+		return reg.NameAt(v)
+	})
+	return nil
+}
+
+// Move 将区域移至 newParent 之下
+//
+// newParent 必须是比当前区域高一级的区域，支持当前区域的所有年份，
+// 且其下没有与当前区域相同 ID 的子区域。
+// 移动之后，当前区域及其子区域的 ID 和全称都会根据 newParent 作相应的修改，
+// 原来的 ID 会通过 [DB.AddSuccessor] 指向新的 ID，可以通过 [DB.Successors] 查找。
+func (db *DB) Move(fullID, newParent string) error {
+	reg, err := db.findRegion(fullID)
+	if err != nil {
+		return err
+	}
+
+	parent := db.Find(newParent)
+	switch {
+	case parent == nil:
+		return fmt.Errorf("%w：%s", ErrParentNotFound, newParent)
+	case parent == reg.parent:
+		return nil
+	case parent.level != reg.level<<1:
+		return fmt.Errorf("%s 不能作为 %s 的上级区域", newParent, fullID)
+	case parent.findItem(reg.id) != nil:
+		return fmt.Errorf("%s 中已经存在相同 ID 的数据项：%s", newParent, reg.id)
+	}
+	for _, v := range reg.versions {
+		if !parent.IsSupported(v) {
+			return fmt.Errorf("%w：%s 不支持 %d", ErrUnknownVersion, newParent, v)
+		}
+	}
+
+	ids := make(map[*Region]string, 10) // 移动之前的 ID
+	reg.walk(id.AllLevel, func(r *Region) bool {
+		ids[r] = r.fullID
+		return true
+	})
+
+	db.resetIndex()
+	reg.parent.items = slices.DeleteFunc(reg.parent.Items(), func(item *Region) bool { return item == reg })
+	parent.items = append(parent.Items(), reg)
+	reg.parent = parent
+	reg.updateFullID()
+	reg.updateFullName()
+
+	for r, old := range ids {
+		if err := db.AddSuccessor(old, r.fullID, slices.Min(r.versions)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *DB) findRegion(fullID string) (*Region, error) {
+	if len(fullID) != id.Length(id.Village) {
+		return nil, fmt.Errorf("%w：%s", ErrInvalidID, fullID)
+	}
+
+	reg := db.Find(fullID)
+	if reg == nil || reg == db.root {
+		return nil, fmt.Errorf("%w：%s", ErrNotFound, fullID)
+	}
+	return reg, nil
+}
+
+// 从当前区域及其子区域中删除 year 年份
+//
+// 返回当前区域是否已经不再支持任何年份。
+func (reg *Region) removeVersion(year int) bool {
+	reg.items = slices.DeleteFunc(reg.Items(), func(item *Region) bool { return item.removeVersion(year) })
+
+	i := slices.Index(reg.versions, year)
+	if i == -1 {
+		return len(reg.versions) == 0
+	}
+
+	reg.versions = slices.Delete(slices.Clone(reg.versions), i, i+1)
+	if len(reg.names) > 0 {
+		reg.resetNames(reg.NameAt)
+	}
+	return len(reg.versions) == 0
+}
+
+// 根据 nameAt 返回的各年份名称重新生成名称的历史记录
+func (reg *Region) resetNames(nameAt func(int) string) {
+	names := make([]*NameRecord, 0, len(reg.names)+1)
+	for _, v := range reg.versions {
+		name := nameAt(v)
+		if i := slices.IndexFunc(names, func(n *NameRecord) bool { return n.Name == name }); i > -1 {
+			names[i].Versions = append(names[i].Versions, v)
+		} else {
+			names = append(names, &NameRecord{Name: name, Versions: []int{v}})
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		reg.names = nil
+	case 1:
+		reg.names = nil
+		reg.name = names[0].Name
+	default:
+		reg.names = names
+		reg.name = reg.NameAt(slices.Max(reg.versions))
+	}
+	reg.updateFullName()
+}
+
+// 根据上一级的 ID 更新当前区域及其子项的 ID
+func (reg *Region) updateFullID() {
+	prefix := ""
+	if p := reg.Parent(); p != nil {
+		prefix = id.Prefix(p.fullID)
+	}
+	reg.fullID = id.Fill(prefix+reg.id, id.Village)
+
+	for _, item := range reg.items {
+		item.updateFullID()
+	}
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+// 重新编码并加载 db，以验证数据的一致性。
+func reloadDB(a *assert.Assertion, db *DB) *DB {
+	data, err := db.marshal()
+	a.NotError(err)
+
+	db2, err := Load(data, db.fullNameSeparator, false)
+	a.NotError(err).NotNil(db2)
+	return db2
+}
+
+func TestDB_RemoveItem(t *testing.T) {
+	a := assert.New(t, false)
+
+	db := newNamesDB(a)
+	a.ErrorIs(db.RemoveItem("330305", 2020), ErrInvalidID).
+		ErrorIs(db.RemoveItem("330306000000", 2020), ErrNotFound).
+		ErrorIs(db.RemoveItem("000000000000", 2020), ErrNotFound).
+		ErrorIs(db.RemoveItem("330305000000", 2001), ErrUnknownVersion)
+
+	a.NotError(db.RemoveItem("330300000000", 2019))
+	r := db.Find("330300000000")
+	a.NotNil(r).
+		Equal(r.Versions(), []int{2018, 2020}).
+		Empty(r.names).
+		Equal(r.Name(), "温州市")
+	r = db.Find("330305000000")
+	a.NotNil(r).
+		Equal(r.Versions(), []int{2018, 2020}).
+		Equal(r.NameAt(2018), "洞头县").
+		Equal(r.NameAt(2020), "洞头区")
+
+	db2 := reloadDB(a, db)
+	a.Equal(db2.Find("330305000000").Versions(), []int{2020, 2018}).
+		Equal(db2.Find("330305000000").NameAt(2018), "洞头县")
+
+	// 删除最后一个年份
+	a.NotError(db.RemoveItem("330305000000", 2018)).
+		NotError(db.RemoveItem("330305000000", 2020)).
+		Nil(db.Find("330305000000")).
+		Empty(db.Find("330300000000").Items())
+
+	// 直接删除
+	a.NotError(db.RemoveItem("330000000000", 0)).
+		Empty(db.Provinces())
+}
+
+func TestDB_RemoveVersion(t *testing.T) {
+	a := assert.New(t, false)
+
+	db := newNamesDB(a)
+	a.ErrorIs(db.RemoveVersion(2001), ErrUnknownVersion)
+
+	a.NotError(db.RemoveVersion(2020)).
+		Equal(db.Versions(), []int{2019, 2018})
+	r := db.Find("330305000000")
+	a.NotNil(r).
+		Equal(r.Versions(), []int{2018, 2019}).
+		Empty(r.names).
+		Equal(r.Name(), "洞头县").
+		Equal(r.FullName(), "浙江省-温州地区-洞头县")
+
+	db2 := reloadDB(a, db)
+	a.Equal(db2.Versions(), []int{2019, 2018}).
+		Equal(db2.Find("330300000000").NameAt(2018), "温州市").
+		Equal(db2.Find("330300000000").NameAt(2019), "温州地区")
+
+	// 二进制格式的数据
+	bin, err := newNamesDB(a).MarshalBinary()
+	a.NotError(err)
+	db2, err = LoadBinary(bin, "-")
+	a.NotError(err).NotNil(db2).
+		NotError(db2.RemoveVersion(2020)).
+		Equal(db2.Find("330305000000").FullName(), "浙江省-温州地区-洞头县").
+		Equal(reloadDB(a, db2).Find("330305000000").Versions(), []int{2019, 2018})
+
+	// 仅支持 2019 的区域会被删除
+	a.True(db.AddVersion(2017)).
+		NotError(db.AddItem("34", "安徽省", 2019)).
+		NotError(db.RemoveVersion(2019)).
+		Nil(db.Find("340000000000")).
+		NotNil(db.Find("330000000000"))
+}
+
+func TestDB_Rename(t *testing.T) {
+	a := assert.New(t, false)
+
+	db := newNamesDB(a)
+	a.ErrorIs(db.Rename("330306000000", "洞头", 2020), ErrNotFound).
+		ErrorIs(db.Rename("330305000000", "洞头", 2001), ErrUnknownVersion).
+		Error(db.Rename("330305000000", "", 2020))
+
+	a.NotError(db.Rename("330300000000", "温州市", 2019))
+	r := db.Find("330305000000")
+	a.Empty(db.Find("330300000000").names).
+		Equal(r.FullName(), "浙江省-温州市-洞头区")
+
+	a.NotError(db.Rename("330305000000", "洞头县", 2020)).
+		Empty(r.names).
+		Equal(r.Name(), "洞头县")
+
+	a.NotError(db.Rename("330000000000", "浙江", 2018))
+	r = db.Find("330000000000")
+	a.Equal(r.Name(), "浙江省").
+		Equal(r.NameAt(2018), "浙江").
+		Equal(db.Find("330305000000").FullNameAt(2018), "浙江-温州市-洞头县")
+
+	a.NotError(db.Rename("330000000000", "浙江", 0)).
+		Empty(r.names).
+		Equal(db.Find("330305000000").FullName(), "浙江-温州市-洞头县")
+
+	db2 := reloadDB(a, db)
+	a.Equal(db2.Find("330305000000").FullName(), "浙江-温州市-洞头县")
+}
+
+func TestDB_Move(t *testing.T) {
+	a := assert.New(t, false)
+
+	db := newNamesDB(a)
+	a.NotError(db.AddItem("3302", "宁波市", 2020)).
+		NotError(db.AddItem("330302", "鹿城区", 2020)).
+		NotError(db.AddItem("330302001", "五马街道", 2020)).
+		NotError(db.AddItem("330205", "洞头区", 2020))
+
+	a.ErrorIs(db.Move("330306000000", "330200000000"), ErrNotFound).
+		ErrorIs(db.Move("330302000000", "340000000000"), ErrParentNotFound).
+		Error(db.Move("330302000000", "330000000000")).
+		Error(db.Move("330305000000", "330200000000")). // ID 冲突
+		NotError(db.Move("330302000000", "330300000000"))
+
+	// 宁波市不支持 2019
+	a.NotError(db.AddItem("330326", "平阳县", 2019)).
+		ErrorIs(db.Move("330326000000", "330200000000"), ErrUnknownVersion).
+		NotNil(db.Find("330326000000"))
+
+	a.NotError(db.Move("330302000000", "330200000000"))
+	r := db.Find("330202001000")
+	a.NotNil(r).
+		Equal(r.FullName(), "浙江省-宁波市-鹿城区-五马街道").
+		Equal(r.Parent().FullID(), "330202000000").
+		Equal(r.Parent().Parent().FullID(), "330200000000").
+		Nil(db.Find("330302000000")).
+		Length(db.Find("330300000000").Items(), 2)
+
+	// 原来的 ID 指向新的 ID
+	a.Equal(db.Successors("330302000000", 2020), []*Region{db.Find("330202000000")}).
+		Equal(db.Successors("330302001000", 2020), []*Region{r})
+
+	db2 := reloadDB(a, db)
+	a.Equal(db2.Find("330202001000").FullName(), "浙江省-宁波市-鹿城区-五马街道")
+}
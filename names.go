@@ -0,0 +1,260 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NameRecord 区域名称的历史记录
+type NameRecord struct {
+	Name     string
+	Versions []int // 使用该名称的年份
+}
+
+// NameAt 指定年份中的区域名称
+//
+// 如果当前区域不支持该年份，返回空值。
+func (r *Region) NameAt(year int) string {
+	if !r.IsSupported(year) {
+		return ""
+	}
+
+	for _, n := range r.names {
+		if slices.Index(n.Versions, year) > -1 {
+			return n.Name
+		}
+	}
+	return r.name
+}
+
+// 搜索时采用的名称
+//
+// year 为 0 表示不限制年份，此时采用最新的名称。
+func (r *Region) searchName(year int) string {
+	if year == 0 {
+		return r.name
+	}
+	return r.NameAt(year)
+}
+
+// FullNameAt 指定年份中的区域全称
+//
+// 与 [Region.FullName] 不同，上一级区域的名称也采用 year 年份中的名称。
+// 如果当前区域不支持该年份，返回空值。
+func (r *Region) FullNameAt(year int) string {
+	if !r.IsSupported(year) {
+		return ""
+	}
+
+	path := r.Path()
+	names := make([]string, 0, len(path))
+	for _, p := range path {
+		names = append(names, p.NameAt(year))
+	}
+	return strings.Join(names, r.db.fullNameSeparator)
+}
+
+// NameHistory 区域名称的历史记录
+//
+// 如果当前区域在所有年份中的名称都相同，那么仅返回一条记录。
+func (r *Region) NameHistory() []*NameRecord {
+	if len(r.names) == 0 {
+		return []*NameRecord{{Name: r.name, Versions: r.versions}}
+	}
+	return r.names
+}
+
+// 将 name 设置为 ver 年份的名称
+func (reg *Region) setName(name string, ver int) error {
+	if reg.IsSupported(ver) {
+		if n := reg.NameAt(ver); n != name {
+			return fmt.Errorf("%s 在 %d 年份中已经存在名称 %s", reg.fullID, ver, n)
+		}
+		return nil
+	}
+
+	if err := reg.setSupported(ver); err != nil {
+		return err
+	}
+
+	if len(reg.names) == 0 {
+		if reg.name == name {
+			return nil
+		}
+
+		versions := slices.DeleteFunc(slices.Clone(reg.versions), func(v int) bool { return v == ver })
+		reg.names = []*NameRecord{{Name: reg.name, Versions: versions}}
+	}
+
+	if i := slices.IndexFunc(reg.names, func(n *NameRecord) bool { return n.Name == name }); i > -1 {
+		reg.names[i].Versions = append(reg.names[i].Versions, ver)
+	} else {
+		reg.names = append(reg.names, &NameRecord{Name: name, Versions: []int{ver}})
+	}
+
+	reg.name = reg.NameAt(slices.Max(reg.versions))
+	reg.updateFullName()
+	return nil
+}
+
+// 根据上一级的全称更新当前区域及其子项的全称
+func (reg *Region) updateFullName() {
+	reg.fullName = reg.name
+	if p := reg.Parent(); p != nil {
+		reg.fullName = p.fullName + reg.db.fullNameSeparator + reg.name
+	}
+
+	for _, item := range reg.items {
+		item.updateFullName()
+	}
+}
+
+func (reg *Region) marshalNames() (string, error) {
+	if len(reg.names) == 0 {
+		return escape(reg.name), nil
+	}
+
+	names := make([]string, 0, len(reg.names))
+	for _, n := range reg.names {
+		mask, err := reg.versionsMask(n.Versions)
+		if err != nil {
+			return "", err
+		}
+		names = append(names, escape(n.Name)+"@"+strconv.Itoa(mask))
+	}
+	return strings.Join(names, ";"), nil
+}
+
+// 解析名称字段
+//
+// 需要在 reg.versions 初始化之后调用。
+func (reg *Region) unmarshalNames(val string) error {
+	var items []string
+	switch {
+	case reg.db.format < 2:
+		reg.name = val
+		return nil
+	case reg.db.format == 2: // 未转义的格式
+		if strings.IndexByte(val, '@') < 0 {
+			reg.name = val
+			return nil
+		}
+		items = strings.Split(val, ";")
+	default:
+		if indexUnescaped(val, '@') < 0 {
+			reg.name = unescape(val)
+			return nil
+		}
+		items = splitUnescaped(val, ';')
+	}
+
+	names := make([]*NameRecord, 0, len(items))
+	count := 0 // 所有名称中的年份数量
+	for _, item := range items {
+		var index int
+		var name string
+		if reg.db.format == 2 {
+			index = strings.LastIndexByte(item, '@')
+			name = item[:max(index, 0)]
+		} else {
+			index = indexUnescaped(item, '@')
+			name = unescape(item[:max(index, 0)])
+		}
+		if index < 0 {
+			return fmt.Errorf("无效的名称格式 %s", val)
+		}
+
+		mask, err := strconv.Atoi(item[index+1:])
+		if err != nil {
+			return err
+		}
+
+		versions := slices.DeleteFunc(reg.maskVersions(mask), func(v int) bool { return !reg.IsSupported(v) })
+		if len(versions) > 0 {
+			names = append(names, &NameRecord{Name: name, Versions: versions})
+			count += len(versions)
+		}
+	}
+
+	for _, ver := range reg.versions { // 每个年份有且仅有一个名称
+		if count != len(reg.versions) || slices.IndexFunc(names, func(n *NameRecord) bool { return slices.Index(n.Versions, ver) > -1 }) == -1 {
+			return fmt.Errorf("名称 %s 与年份不匹配", val)
+		}
+	}
+
+	switch len(names) {
+	case 0: // 所有名称都被过滤，reg.versions 也必然为空。
+	case 1:
+		reg.name = names[0].Name
+	default:
+		reg.names = names
+		reg.name = reg.NameAt(slices.Max(reg.versions))
+	}
+	return nil
+}
+
+// 需要在名称中转义的字符
+const escapedChars = `\\:{}@;`
+
+// 对名称中的特殊字符进行转义
+func escape(s string) string {
+	if !strings.ContainsAny(s, escapedChars) {
+		return s
+	}
+
+	b := strings.Builder{}
+	b.Grow(len(s) + 4)
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(escapedChars, s[i]) > -1 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// 还原由 escape 转义的内容
+func unescape(s string) string {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+
+	b := strings.Builder{}
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// 查找 s 中第一个未被转义的 sep 的位置
+func indexUnescaped[T ~string | ~[]byte](s T, sep byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return i
+		}
+	}
+	return -1
+}
+
+// 以未被转义的 sep 分割 s
+func splitUnescaped(s string, sep byte) []string {
+	items := make([]string, 0, 2)
+	for index := indexUnescaped(s, sep); index > -1; index = indexUnescaped(s, sep) {
+		items = append(items, s[:index])
+		s = s[index+1:]
+	}
+	return append(items, s)
+}
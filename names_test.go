@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func newNamesDB(a *assert.Assertion) *DB {
+	db := NewDB(WithSeparator("-"))
+	a.True(db.AddVersion(2020)).True(db.AddVersion(2019)).True(db.AddVersion(2018))
+
+	a.NotError(db.AddItem("330000000000", "浙江省", 2018)).
+		NotError(db.AddItem("330300000000", "温州市", 2018)).
+		NotError(db.AddItem("330305000000", "洞头县", 2018)).
+		NotError(db.AddItem("330000000000", "浙江省", 2019)).
+		NotError(db.AddItem("330300000000", "温州地区", 2019)).
+		NotError(db.AddItem("330305000000", "洞头县", 2019)).
+		NotError(db.AddItem("330000000000", "浙江省", 2020)).
+		NotError(db.AddItem("330300000000", "温州市", 2020)).
+		NotError(db.AddItem("330305000000", "洞头区", 2020))
+
+	return db
+}
+
+func TestRegion_NameAt(t *testing.T) {
+	a := assert.New(t, false)
+	db := newNamesDB(a)
+
+	r := db.Find("330305000000")
+	a.NotNil(r).
+		Equal(r.Name(), "洞头区").
+		Equal(r.FullName(), "浙江省-温州市-洞头区").
+		Equal(r.NameAt(2018), "洞头县").
+		Equal(r.NameAt(2019), "洞头县").
+		Equal(r.NameAt(2020), "洞头区").
+		Equal(r.NameAt(2001), "").
+		Equal(r.FullNameAt(2019), "浙江省-温州地区-洞头县").
+		Equal(r.FullNameAt(2018), "浙江省-温州市-洞头县").
+		Equal(r.FullNameAt(2001), "")
+
+	h := r.NameHistory()
+	a.Length(h, 2).
+		Equal(h[0], &NameRecord{Name: "洞头县", Versions: []int{2018, 2019}}).
+		Equal(h[1], &NameRecord{Name: "洞头区", Versions: []int{2020}})
+
+	p := db.Find("330000000000")
+	a.Equal(p.NameHistory(), []*NameRecord{{Name: "浙江省", Versions: []int{2018, 2019, 2020}}})
+
+	// 同一年份不同的名称
+	a.ErrorString(db.AddItem("330305000000", "洞头", 2020), "已经存在名称")
+	a.NotError(db.AddItem("330305000000", "洞头区", 2020))
+}
+
+func TestRegion_marshalNames(t *testing.T) {
+	a := assert.New(t, false)
+	db := newNamesDB(a)
+
+	data, err := db.marshal()
+	a.NotError(err).
+		Equal(string(data), "3:[2020,2019,2018]:::0:1{33:浙江省:7:1{03:温州市@5;温州地区@2:7:1{05:洞头县@6;洞头区@1:7:0{}}}}")
+
+	db2, err := Load(data, "-", false)
+	a.NotError(err).NotNil(db2)
+	r := db2.Find("330305000000")
+	a.NotNil(r).
+		Equal(r.Name(), "洞头区").
+		Equal(r.FullName(), "浙江省-温州市-洞头区").
+		Equal(r.NameAt(2019), "洞头县").
+		Equal(r.FullNameAt(2019), "浙江省-温州地区-洞头县").
+		Equal(r.NameHistory(), []*NameRecord{
+			{Name: "洞头县", Versions: []int{2019, 2018}},
+			{Name: "洞头区", Versions: []int{2020}},
+		})
+
+	// 只加载部分年份
+	db2, err = Load(data, "-", false, 2019, 2018)
+	a.NotError(err).NotNil(db2)
+	r = db2.Find("330305000000")
+	a.NotNil(r).
+		Equal(r.Name(), "洞头县").
+		Equal(r.FullName(), "浙江省-温州地区-洞头县").
+		Length(r.NameHistory(), 1)
+
+	db2, err = Load(data, "-", false, 2020)
+	a.NotError(err).NotNil(db2)
+	r = db2.Find("330305000000")
+	a.NotNil(r).
+		Equal(r.FullName(), "浙江省-温州市-洞头区").
+		Length(r.NameHistory(), 1)
+}
+
+func TestRegion_escapeNames(t *testing.T) {
+	a := assert.New(t, false)
+
+	db := NewDB(WithSeparator("-"))
+	a.True(db.AddVersion(2020)).True(db.AddVersion(2019))
+	a.NotError(db.AddItem("330000000000", `a:{b}\c`, 2020)).
+		NotError(db.AddItem("330000000000", `d@e;f`, 2019)).
+		NotError(db.AddItem("340000000000", `x;y@z`, 2020))
+
+	data, err := db.marshal()
+	a.NotError(err).
+		Equal(string(data), `3:[2020,2019]:::0:2{33:a\:\{b\}\\c@1;d\@e\;f@2:3:0{}34:x\;y\@z:1:0{}}`)
+
+	db2, err := Load(data, "-", false)
+	a.NotError(err).NotNil(db2)
+	r := db2.Find("330000000000")
+	a.NotNil(r).
+		Equal(r.Name(), `a:{b}\c`).
+		Equal(r.NameAt(2019), `d@e;f`)
+	a.Equal(db2.Find("340000000000").Name(), `x;y@z`)
+
+	// 版本 2 的数据格式不存在转义
+	db2, err = Load([]byte(`2:[2020,2019]:::0:1{33:a\b@1;c@2:3:0{}}`), "-", false)
+	a.NotError(err).NotNil(db2)
+	r = db2.Find("330000000000")
+	a.Equal(r.Name(), `a\b`).Equal(r.NameAt(2019), "c")
+}
+
+func TestEscape(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(escape("温州"), "温州").
+		Equal(escape(`a:b{c}d\e@f;`), `a\:b\{c\}d\\e\@f\;`).
+		Equal(unescape(`a\:b\{c\}d\\e\@f\;`), `a:b{c}d\e@f;`).
+		Equal(unescape(`abc\`), `abc\`)
+
+	a.Equal(indexUnescaped(`a\:b:c`, ':'), 4).
+		Equal(indexUnescaped([]byte(`a\\:b`), ':'), 3).
+		Equal(indexUnescaped(`abc`, ':'), -1)
+
+	a.Equal(splitUnescaped(`a\;b;c`, ';'), []string{`a\;b`, "c"}).
+		Equal(splitUnescaped(`abc`, ';'), []string{"abc"})
+}
+
+func TestView_Search_names(t *testing.T) {
+	a := assert.New(t, false)
+
+	for _, index := range []bool{false, true} {
+		db := newNamesDB(a)
+		db.initIndex(index)
+
+		list := db.At(2019).Search(&Options{Text: "温州地区"})
+		a.Length(list, 1).Equal(list[0].FullID(), "330300000000")
+
+		list = db.At(2019).Search(&Options{Text: "洞头县"})
+		a.Length(list, 1).Equal(list[0].FullID(), "330305000000")
+
+		a.Empty(db.At(2020).Search(&Options{Text: "洞头县"})).
+			Length(db.At(2020).Search(&Options{Text: "洞头区"}), 1).
+			Empty(db.At(2018).Search(&Options{Text: "温州地区"})).
+			Empty(db.Search(&Options{Text: "温州地区"})) // 未指定年份时仅匹配最新的名称
+
+		list = db.At(2019).Search(&Options{Text: "温州地区洞头县", Normalize: true})
+		a.Length(list, 1).Equal(list[0].FullID(), "330305000000")
+	}
+
+	db := newNamesDB(a)
+	a.Length(db.At(2019).SearchRanked(&Options{Text: "温州地区"}), 1).
+		Equal(db.At(2019).SearchRanked(&Options{Text: "洞头县"})[0].Score, ScoreExact).
+		Equal(db.At(2019).SearchPath("温州地区洞头县", 0)[0].Score, 65)
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"io/fs"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+// Option 加载或创建数据时的选项
+type Option func(*options)
+
+type options struct {
+	separator string
+	years     []int
+	fs        fs.FS
+	maxLevel  id.Level
+	provinces []string
+
+	placeholder *string
+	index       bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithSeparator 指定 [Region.FullName] 中各级名称之间的分隔符
+func WithSeparator(sep string) Option { return func(o *options) { o.separator = sep } }
+
+// WithYears 仅加载指定年份的数据
+//
+// 如果未指定或为空，则加载所有年份的数据。
+func WithYears(years ...int) Option { return func(o *options) { o.years = years } }
+
+// WithFS 指定 [Open] 读取文件时采用的文件系统
+//
+// 如果未指定，则采用本地文件系统。
+func WithFS(fsys fs.FS) Option { return func(o *options) { o.fs = fsys } }
+
+// WithMaxLevel 仅加载 level 及以上级别的区域
+//
+// 比如指定为 [id.County]，则只加载省、市和县三级数据，
+// 被忽略的区域不会占用内存，可以减少加载时间和内存的使用。
+func WithMaxLevel(level id.Level) Option { return func(o *options) { o.maxLevel = level } }
+
+// WithProvinces 仅加载指定省份的数据
+//
+// ids 为省级区域的 ID，可以是两位的 ID，也可以是 12 位的完整 ID。
+func WithProvinces(ids ...string) Option {
+	return func(o *options) {
+		for _, i := range ids {
+			if len(i) >= id.Length(id.Province) {
+				o.provinces = append(o.provinces, i[:id.Length(id.Province)])
+			}
+		}
+	}
+}
+
+// WithPlaceholder 由 [DB.AddItem] 自动创建不存在的上级区域
+//
+// name 为自动创建的区域的名称，之后再添加该区域时，会以真实的名称替换 name。
+// 仅对 [NewDB] 和 [Import] 有效。
+func WithPlaceholder(name string) Option { return func(o *options) { o.placeholder = &name } }
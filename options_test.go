@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+func TestWithProvinces(t *testing.T) {
+	a := assert.New(t, false)
+
+	o := newOptions([]Option{WithProvinces("33", "340000000000", "3")})
+	a.Equal(o.provinces, []string{"33", "34"})
+
+	db, err := Load(data, "-", false)
+	a.NotError(err)
+	bin, err := db.MarshalBinary()
+	a.NotError(err)
+
+	for _, data := range [][]byte{data, bin} {
+		db, err := LoadReader(bytes.NewReader(data), WithProvinces("34"))
+		a.NotError(err).NotNil(db).
+			Length(db.Provinces(), 1).
+			Nil(db.Find("330000000000")).
+			NotNil(db.Find("340300000000"))
+	}
+}
+
+func TestWithMaxLevel(t *testing.T) {
+	a := assert.New(t, false)
+
+	text, err := newNamesDB(a).marshal()
+	a.NotError(err)
+	db, err := Load(text, "-", false)
+	a.NotError(err)
+	bin, err := db.MarshalBinary()
+	a.NotError(err)
+
+	for _, data := range [][]byte{text, bin} {
+		db, err := LoadReader(bytes.NewReader(data), WithMaxLevel(id.City))
+		a.NotError(err).NotNil(db).
+			NotNil(db.Find("330300000000")).
+			Nil(db.Find("330305000000")).
+			Empty(db.Find("330300000000").Items())
+
+		// 输出裁剪之后的数据
+		path := filepath.Join(t.TempDir(), "trim.db")
+		a.NotError(db.Dump(path, true))
+		db, err = LoadFile(path, "-", true)
+		a.NotError(err).NotNil(db).
+			Equal(db.Find("330300000000").FullNameAt(2019), "浙江省-温州地区").
+			Nil(db.Find("330305000000"))
+	}
+
+	db, err = LoadReader(bytes.NewReader(text), WithMaxLevel(id.Province), WithProvinces("11"))
+	a.NotError(err).NotNil(db).Empty(db.Provinces())
+
+	// 被忽略的数据也需要验证格式
+	_, err = LoadReader(bytes.NewReader([]byte("3:[2020]:::1:1{33:a:1:1{01:b:1:1{}}}")), WithMaxLevel(id.Province))
+	a.ErrorString(err, "期望 ':'")
+	_, err = LoadReader(bytes.NewReader([]byte("3:[2020]:::1:1{33:a:1:1{01:b:1:0{}}}")), WithProvinces("34"))
+	a.NotError(err)
+}
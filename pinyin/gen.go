@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+//go:build ignore
+
+// 根据 https://github.com/mozillazg/pinyin-data 生成 table.txt
+//
+// 每个汉字仅保留第一个读音，并去掉声调，ü 以 v 表示。
+// 多音字在地名中的读音由 pinyin.go 中的 overrides 和 phrases 修正。
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const source = "https://raw.githubusercontent.com/mozillazg/pinyin-data/master/pinyin.txt"
+
+var tones = map[rune]rune{
+	'ā': 'a', 'á': 'a', 'ǎ': 'a', 'à': 'a',
+	'ē': 'e', 'é': 'e', 'ě': 'e', 'è': 'e', 'ê': 'e', 'ế': 'e', 'ề': 'e',
+	'ī': 'i', 'í': 'i', 'ǐ': 'i', 'ì': 'i',
+	'ō': 'o', 'ó': 'o', 'ǒ': 'o', 'ò': 'o',
+	'ū': 'u', 'ú': 'u', 'ǔ': 'u', 'ù': 'u',
+	'ü': 'v', 'ǖ': 'v', 'ǘ': 'v', 'ǚ': 'v', 'ǜ': 'v',
+	'ń': 'n', 'ň': 'n', 'ǹ': 'n', 'ḿ': 'm',
+}
+
+func main() {
+	input := flag.String("input", source, "pinyin-data 的 pinyin.txt 文件，可以是 URL")
+	output := flag.String("output", "table.txt", "输出的文件")
+	flag.Parse()
+
+	if err := gen(*input, *output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func gen(input, output string) error {
+	var r io.ReadCloser
+	if strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://") {
+		resp, err := http.Get(input)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("下载 %s 时返回 %s", input, resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		r = f
+	}
+	defer r.Close()
+
+	syllables := map[string][]rune{}
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		// U+4E2D: zhōng,zhòng  # 中
+		code, readings, found := strings.Cut(line, ":")
+		if !found || !strings.HasPrefix(code, "U+") {
+			return fmt.Errorf("无效的格式 %s", line)
+		}
+		c, err := strconv.ParseUint(code[2:], 16, 32)
+		if err != nil {
+			return err
+		}
+		readings, _, _ = strings.Cut(readings, "#")
+		reading, _, _ := strings.Cut(strings.TrimSpace(readings), ",")
+
+		if py := plain(reading); py != "" {
+			syllables[py] = append(syllables[py], rune(c))
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(syllables))
+	for k := range syllables {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	w := &strings.Builder{}
+	w.WriteString("# 由 gen.go 根据 " + source + " 生成，请勿手动修改。\n")
+	w.WriteString("# pinyin-data 采用 MIT 许可证，版权归 mozillazg 所有。\n")
+	w.WriteString("# 格式为：拼音\\t汉字列表\n")
+	for _, k := range keys {
+		chars := syllables[k]
+		slices.Sort(chars)
+		w.WriteString(k + "\t" + string(chars) + "\n")
+	}
+	return os.WriteFile(output, []byte(w.String()), 0o644)
+}
+
+// 去掉声调以及无法识别的字符
+func plain(s string) string {
+	b := &strings.Builder{}
+	for _, r := range s {
+		if t, found := tones[r]; found {
+			r = t
+		}
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		} else if !unicode.Is(unicode.Mn, r) { // 组合用的声调符号直接忽略
+			return ""
+		}
+	}
+	return b.String()
+}
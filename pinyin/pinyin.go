@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+//go:generate go run gen.go
+
+// Package pinyin 将区域名称转换为拼音
+//
+// 拼音不带声调，ü 以 v 表示，比如吕梁为 lvliang。
+// 多音字优先采用其在地名中的读音，比如六安为 luan，蚌埠为 bengbu。
+package pinyin
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+//go:embed table.txt
+var table string
+
+var (
+	chars     map[rune]string
+	charsOnce sync.Once
+)
+
+// 多音字在地名中最常用的读音，优先于 table.txt 中的读音。
+var overrides = map[rune]string{
+	'都': "du",
+	'长': "chang",
+	'厦': "xia",
+	'藏': "zang",
+	'堡': "bao",
+}
+
+// 地名中的特殊读音，优先于 overrides 和 table.txt。
+var phrases = map[string][]string{
+	"六安": {"lu", "an"},
+	"六合": {"lu", "he"},
+	"蚌埠": {"beng", "bu"},
+	"番禺": {"pan", "yu"},
+	"铅山": {"yan", "shan"},
+	"乐亭": {"lao", "ting"},
+	"乐清": {"yue", "qing"},
+	"重庆": {"chong", "qing"},
+	"长子": {"zhang", "zi"},
+	"莘庄": {"xin", "zhuang"},
+	"蔚县": {"yu", "xian"},
+	"尉犁": {"yu", "li"},
+	"洪洞": {"hong", "tong"},
+	"涡阳": {"guo", "yang"},
+	"浚县": {"xun", "xian"},
+	"犍为": {"qian", "wei"},
+	"珲春": {"hun", "chun"},
+	"筠连": {"jun", "lian"},
+	"牟平": {"mu", "ping"},
+	"单县": {"shan", "xian"},
+	"解州": {"xie", "zhou"},
+	"泌阳": {"bi", "yang"},
+	"枞阳": {"zong", "yang"},
+	"吴堡": {"wu", "bu"},
+	"百色": {"bai", "se"},
+	"东阿": {"dong", "e"},
+	"会稽": {"kuai", "ji"},
+	"黄陂": {"huang", "pi"},
+	"繁峙": {"fan", "shi"},
+}
+
+// 最长的词组的字符数量
+var maxPhrase = func() int {
+	size := 0
+	for k := range phrases {
+		size = max(size, utf8.RuneCountInString(k))
+	}
+	return size
+}()
+
+func loadTable() {
+	chars = make(map[rune]string, 45000)
+	for _, line := range strings.Split(table, "\n") {
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		py, list, _ := strings.Cut(line, "\t")
+		for _, c := range list {
+			chars[c] = py
+		}
+	}
+
+	for c, py := range overrides {
+		chars[c] = py
+	}
+}
+
+// Convert 将 s 转换为拼音
+//
+// 返回的每个元素对应 s 中的一个字符，汉字为其拼音，
+// 其它字符原样返回，字母会被转换为小写。
+func Convert(s string) []string {
+	charsOnce.Do(loadTable)
+
+	runes := []rune(s)
+	list := make([]string, 0, len(runes))
+	for i := 0; i < len(runes); {
+		if n, py := matchPhrase(runes[i:]); n > 0 {
+			list = append(list, py...)
+			i += n
+			continue
+		}
+
+		if py, found := chars[runes[i]]; found {
+			list = append(list, py)
+		} else {
+			list = append(list, strings.ToLower(string(runes[i])))
+		}
+		i++
+	}
+
+	return list
+}
+
+// 查找以 runes 开头的最长的词组
+func matchPhrase(runes []rune) (int, []string) {
+	for n := min(maxPhrase, len(runes)); n > 1; n-- {
+		if py, found := phrases[string(runes[:n])]; found {
+			return n, py
+		}
+	}
+	return 0, nil
+}
+
+// Full 返回 s 的全拼
+//
+//	温州市 => wenzhoushi
+func Full(s string) string { return strings.Join(Convert(s), "") }
+
+// Initials 返回 s 的拼音首字母
+//
+//	温州市 => wzs
+func Initials(s string) string {
+	list := Convert(s)
+	b := &strings.Builder{}
+	b.Grow(len(list))
+	for _, py := range list {
+		r, _ := utf8.DecodeRuneInString(py)
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package pinyin
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestConvert(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(Convert("温州市"), []string{"wen", "zhou", "shi"}).
+		Equal(Convert("第1村A"), []string{"di", "1", "cun", "a"}).
+		Equal(Convert(""), []string{})
+
+	// 多音字
+	a.Equal(Convert("六安市"), []string{"lu", "an", "shi"}).
+		Equal(Convert("六盘水市"), []string{"liu", "pan", "shui", "shi"}).
+		Equal(Convert("蚌埠市"), []string{"beng", "bu", "shi"}).
+		Equal(Convert("成都市"), []string{"cheng", "du", "shi"}).
+		Equal(Convert("长沙市"), []string{"chang", "sha", "shi"}).
+		Equal(Convert("长子县"), []string{"zhang", "zi", "xian"}).
+		Equal(Convert("重庆市"), []string{"chong", "qing", "shi"}).
+		Equal(Convert("厦门市"), []string{"xia", "men", "shi"})
+}
+
+func TestFull(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(Full("温州市"), "wenzhoushi").
+		Equal(Full("吕梁市"), "lvliangshi").
+		Equal(Full("番禺区"), "panyuqu").
+		Equal(Full("WZ"), "wz")
+}
+
+func TestInitials(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(Initials("温州市"), "wzs").
+		Equal(Initials("鄂尔多斯市"), "eedss").
+		Equal(Initials("六安"), "la").
+		Equal(Initials(""), "")
+}
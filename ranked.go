@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/issue9/cnregion/v2/pinyin"
+)
+
+// [Match.Score] 的取值
+const (
+	ScoreExact    = 100 // 名称完全相同
+	ScoreShort    = 90  // 去掉后缀之后相同，比如温州与温州市。
+	ScorePath     = 85  // 与多级区域的名称相匹配，比如杭州西湖与西湖区，仅在 [Options.Normalize] 为 true 时有效。
+	ScorePrefix   = 80  // 名称以查询内容开头
+	ScoreContains = 60  // 名称包含查询内容
+	ScorePinyin   = 50  // 读音相同，比如温洲与温州；或是在 [MatchPinyin] 模式下名称或简称的拼音或首字母相同。
+	ScoreTypo     = 40  // 有一个字不同，比如乌鲁本齐与乌鲁木齐，每多一个字不同减 10 分；仅对不少于三个字的内容有效。
+
+	ScorePinyinContains = 30 // 在 [MatchPinyin] 模式下拼音或首字母包含查询内容
+)
+
+// Match [DB.SearchRanked] 返回的结果
+type Match struct {
+	Region *Region
+	Score  int // 匹配度，值越大越匹配，取值为 Score 开头的常量。
+}
+
+// 排序搜索过程中的状态
+type ranker struct {
+	*searcher
+	stem  string // text 的简称
+	runes []rune // stem 的字符列表，用于计算编辑距离。
+	sound string // stem 的拼音，用于匹配同音字，仅在 stem 不少于两个字时有值。
+	typos int    // 允许的编辑距离
+	list  []Match
+}
+
+// SearchRanked 按匹配度排序的搜索功能
+//
+// 与 [DB.Search] 不同，opt.Text 不能为空，且不要求区域名称包含 opt.Text，
+// 去掉后缀之后相同、读音相同以及个别字不同的区域也会被返回，
+// 返回结果按 [Match.Score] 从高到低排列，分数相同时级别高的在前，其它的按遍历的顺序排列。
+// opt.Max 在排序之后才会应用，即返回的是匹配度最高的 opt.Max 个区域。
+//
+// 不会修改 opt 的内容，可以在多个协程中使用同一个 opt。
+func (db *DB) SearchRanked(opt *Options) []Match { return db.searchRanked(opt, 0) }
+
+// [DB.SearchRanked] 和 [View.SearchRanked] 的实现
+//
+// 与 search 共用 [Options] 的解析，但需要遍历所有符合条件的区域之后才能排序和截取。
+func (db *DB) searchRanked(opt *Options, year int) []Match {
+	if opt == nil || opt.Text == "" {
+		panic("参数 opt.Text 不能为空")
+	}
+
+	r, s := db.newSearcher(opt, year)
+	if r == nil {
+		return nil
+	}
+
+	stem := shortName(opt.Text)
+	rk := &ranker{
+		searcher: s,
+		stem:     stem,
+		runes:    []rune(stem),
+		list:     make([]Match, 0, 100),
+	}
+	if len(rk.runes) > 1 { // 单个字不作容错处理
+		rk.sound = pinyin.Full(stem)
+	}
+	switch n := len(rk.runes); { // 两个字的名称如果允许错字，温州会匹配到福州、苏州等大量区域。
+	case n >= 5:
+		rk.typos = 2
+	case n >= 3:
+		rk.typos = 1
+	}
+
+	r.rank(rk)
+
+	slices.SortStableFunc(rk.list, func(a, b Match) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		return int(b.Region.level) - int(a.Region.level)
+	})
+
+	if opt.Max > 0 && len(rk.list) > opt.Max {
+		rk.list = rk.list[:opt.Max]
+	}
+	return rk.list
+}
+
+func (reg *Region) rank(rk *ranker) {
+	if !reg.isSupportedAt(rk.year) { // 子区域的年份不会超出当前区域的年份，无须再遍历。
+		return
+	}
+
+	if reg.level != 0 && (reg.level&rk.level == reg.level) { // level == 0 只有根元素才有
+		if score := rk.score(reg); score > 0 {
+			rk.list = append(rk.list, Match{Region: reg, Score: score})
+		}
+	}
+
+	for _, item := range reg.Items() {
+		item.rank(rk)
+	}
+}
+
+// 计算 reg 的匹配度，0 表示不匹配。
+func (rk *ranker) score(reg *Region) int {
+	name := reg.searchName(rk.year)
+	short := shortName(name)
+	switch {
+	case name == rk.text:
+		return ScoreExact
+	case short == rk.stem:
+		return ScoreShort
+	case rk.short != "" && strings.Contains(rk.text, short) && matchPath(rk.text, reg.Path(), rk.year):
+		return ScorePath
+	case strings.HasPrefix(name, rk.text):
+		return ScorePrefix
+	case strings.Contains(name, rk.text):
+		return ScoreContains
+	}
+
+	if rk.py != "" {
+		full, initials := pinyin.Full(name), pinyin.Initials(name)
+		switch {
+		case full == rk.py || initials == rk.py,
+			strings.HasPrefix(full, rk.py) && pinyin.Full(short) == rk.py,
+			strings.HasPrefix(initials, rk.py) && pinyin.Initials(short) == rk.py:
+			return ScorePinyin
+		case strings.Contains(full, rk.py) || strings.Contains(initials, rk.py):
+			return ScorePinyinContains
+		}
+	}
+
+	size := utf8.RuneCountInString(short)
+	if rk.sound != "" && size == len(rk.runes) && pinyin.Full(short) == rk.sound {
+		return ScorePinyin
+	}
+
+	if rk.typos == 0 || abs(size-len(rk.runes)) > rk.typos {
+		return 0
+	}
+	if d := editDistance([]rune(short), rk.runes); d <= rk.typos {
+		return ScoreTypo - (d-1)*10
+	}
+	return 0
+}
+
+// 计算 a 与 b 之间的编辑距离
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := range a {
+		curr[0] = i + 1
+		for j := range b {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
+			}
+			curr[j+1] = min(prev[j+1]+1, curr[j]+1, prev[j]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+func matches(list []Match) map[string]int {
+	m := make(map[string]int, len(list))
+	for _, item := range list {
+		m[item.Region.FullName()] = item.Score
+	}
+	return m
+}
+
+func TestDB_SearchRanked(t *testing.T) {
+	a := assert.New(t, false)
+	db := newSearchDB(a)
+
+	a.Equal(matches(db.SearchRanked(&Options{Text: "温州市"})), map[string]int{"浙江省-温州市": ScoreExact}).
+		Equal(matches(db.SearchRanked(&Options{Text: "温州"})), map[string]int{"浙江省-温州市": ScoreShort}).
+		Equal(matches(db.SearchRanked(&Options{Text: "温州地区"})), map[string]int{"浙江省-温州市": ScoreShort}).
+		Equal(matches(db.SearchRanked(&Options{Text: "温洲"})), map[string]int{"浙江省-温州市": ScorePinyin}).
+		Equal(matches(db.SearchRanked(&Options{Text: "乌鲁本齐"})), map[string]int{"新疆维吾尔自治区-乌鲁木齐市": ScoreTypo}).
+		Equal(matches(db.SearchRanked(&Options{Text: "杭"})), map[string]int{"浙江省-杭州市": ScorePrefix}).
+		Empty(db.SearchRanked(&Options{Text: "北京"}))
+
+	// 排序
+	list := db.SearchRanked(&Options{Text: "西湖"})
+	a.Length(list, 3).
+		Equal(list[0].Region.FullID(), "330106000000"). // 级别高的在前
+		Equal(list[0].Score, ScoreShort).
+		Equal(list[1].Region.FullID(), "330302001001").
+		Equal(list[1].Score, ScoreShort).
+		Equal(list[2].Region.FullID(), "330106001001").
+		Equal(list[2].Score, ScoreContains)
+
+	list = db.SearchRanked(&Options{Text: "州"})
+	a.Length(list, 3).
+		Equal(list[0].Region.FullID(), "330300000000").
+		Equal(list[1].Region.FullID(), "330100000000").
+		Equal(list[2].Region.FullID(), "350100000000")
+
+	// Max 在排序之后应用
+	a.Equal(db.Search(&Options{Text: "西湖", Max: 1})[0].FullID(), "330302001001")
+	list = db.SearchRanked(&Options{Text: "西湖", Max: 1})
+	a.Length(list, 1).Equal(list[0].Region.FullID(), "330106000000")
+
+	// Parent 和 Level
+	a.Equal(matches(db.SearchRanked(&Options{Text: "西湖", Parent: "330300000000"})), map[string]int{"浙江省-温州市-鹿城区-五马街道-西湖村": ScoreShort}).
+		Equal(matches(db.SearchRanked(&Options{Text: "西湖", Level: id.Village})), map[string]int{
+			"浙江省-温州市-鹿城区-五马街道-西湖村":   ScoreShort,
+			"浙江省-杭州市-西湖区-北山街道-小西湖社区": ScoreContains,
+		}).
+		Empty(db.SearchRanked(&Options{Text: "西湖", Parent: "110000000000"}))
+
+	// Normalize
+	list = db.SearchRanked(&Options{Text: "杭州西湖", Normalize: true})
+	a.Length(list, 1).
+		Equal(list[0].Region.FullID(), "330106000000").
+		Equal(list[0].Score, ScorePath)
+
+	// MatchPinyin
+	a.Equal(matches(db.SearchRanked(&Options{Text: "wz", Mode: MatchPinyin})), map[string]int{"浙江省-温州市": ScorePinyin}).
+		Equal(matches(db.SearchRanked(&Options{Text: "wulu", Mode: MatchPinyin})), map[string]int{"新疆维吾尔自治区-乌鲁木齐市": ScorePinyinContains})
+
+	a.PanicString(func() {
+		db.SearchRanked(&Options{})
+	}, "参数 opt.Text 不能为空")
+}
+
+func TestView_SearchRanked(t *testing.T) {
+	a := assert.New(t, false)
+	db := newSearchDB(a)
+
+	a.Length(db.At(2020).SearchRanked(&Options{Text: "鼓楼"}), 2).
+		Length(db.At(2019).SearchRanked(&Options{Text: "鼓楼"}), 1).
+		Empty(db.At(2001).SearchRanked(&Options{Text: "鼓楼"}))
+}
+
+func TestEditDistance(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(editDistance([]rune("乌鲁木齐"), []rune("乌鲁木齐")), 0).
+		Equal(editDistance([]rune("乌鲁木齐"), []rune("乌鲁本齐")), 1).
+		Equal(editDistance([]rune("乌鲁木齐"), []rune("乌木齐")), 1).
+		Equal(editDistance([]rune("乌鲁木齐"), []rune("鲁乌木齐")), 2).
+		Equal(editDistance([]rune(""), []rune("温州")), 2).
+		Equal(editDistance([]rune("温州"), []rune("")), 2)
+}
@@ -5,69 +5,203 @@
 package cnregion
 
 import (
-	"bytes"
+	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"slices"
 	"strconv"
+	"sync/atomic"
+	"unicode"
 
 	"github.com/issue9/errwrap"
 
 	"github.com/issue9/cnregion/v2/id"
+	"github.com/issue9/cnregion/v2/pinyin"
 )
 
 // Region 表示单个区域
 type Region struct {
 	id       string
-	name     string
+	name     string        // 最新年份的名称
+	names    []*NameRecord // 名称的历史记录，仅在不同年份的名称不同时才有值
 	items    []*Region
 	versions []int // 支持的版本号列表
 
 	// 以下数据不会写入数据文件中
 
-	fullName string // 全名
-	fullID   string
-	db       *DB
-	level    id.Level
+	fullName    string // 全名
+	fullID      string
+	db          *DB
+	level       id.Level
+	parent      *Region
+	placeholder bool // 是否为 WithPlaceholder 自动创建的区域
+
+	// 二进制格式的数据
+	node   uint32      // 在二进制数据中的节点索引
+	loaded atomic.Bool // 子项是否已经解码
 }
 
 // Provinces 省份列表
-func (db *DB) Provinces() []*Region { return db.root.items }
+func (db *DB) Provinces() []*Region { return db.root.Items() }
 
 func (r *Region) ID() string       { return r.id }       // 区域的 ID，不包括后缀 0 和上一级的 ID
-func (r *Region) Name() string     { return r.name }     // 区域的名称
+func (r *Region) Name() string     { return r.name }     // 区域的名称，如果有多个名称，返回最新年份的名称
 func (r *Region) FullName() string { return r.fullName } // 区域的全称，包括上一级的名称
 func (r *Region) FullID() string   { return r.fullID }   // 区域的 ID，包括后缀的 0 以及上一级的 ID，长度为 12
 func (r *Region) Versions() []int  { return r.versions } // 支持的年份版本
-func (r *Region) Items() []*Region { return r.items }    // 子项
+func (r *Region) Level() id.Level  { return r.level }    // 区域的级别
+
+// Pinyin 区域名称的全拼
+//
+// 不带声调且全部为小写，比如温州市返回 wenzhoushi，具体规则可参考 [pinyin.Full]。
+func (r *Region) Pinyin() string { return pinyin.Full(r.name) }
+
+// Items 子项
+//
+// 对于由 [LoadBinary] 等函数加载的数据，子项会在第一次调用时才解码。
+func (r *Region) Items() []*Region {
+	if r.db == nil || r.db.bin == nil || r.loaded.Load() {
+		return r.items
+	}
+
+	r.db.bin.mux.Lock()
+	defer r.db.bin.mux.Unlock()
+	if !r.loaded.Load() {
+		r.items = r.db.bin.decodeItems(r)
+		r.loaded.Store(true)
+	}
+	return r.items
+}
+
+// Parent 上一级区域
+//
+// 省级区域和 [DB.Districts] 返回的大区返回 nil。
+func (r *Region) Parent() *Region {
+	if r.parent == nil || r.parent.level == 0 { // level == 0 只有根元素才有
+		return nil
+	}
+	return r.parent
+}
+
+// Ancestors 所有的上级区域
+//
+// 按由近及远的顺序返回，即第一个元素为 [Region.Parent]，最后一个元素为省级区域。
+func (r *Region) Ancestors() []*Region {
+	list := make([]*Region, 0, 4)
+	for p := r.Parent(); p != nil; p = p.Parent() {
+		list = append(list, p)
+	}
+	return list
+}
+
+// Path 从省级区域到当前区域的路径
+//
+// 最后一个元素为当前区域本身。
+func (r *Region) Path() []*Region {
+	ancestors := r.Ancestors()
+	path := make([]*Region, 0, len(ancestors)+1)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		path = append(path, ancestors[i])
+	}
+	return append(path, r)
+}
+
+// 区域的 JSON 表示
+type regionJSON struct {
+	ID       string        `json:"id"`
+	FullID   string        `json:"fullID"`
+	Name     string        `json:"name"`
+	FullName string        `json:"fullName"`
+	Level    id.Level      `json:"level"`
+	Versions []int         `json:"versions"`
+	Items    []*regionJSON `json:"items,omitempty"`
+}
+
+type regionItems struct {
+	r     *Region
+	depth int
+}
+
+// MarshalJSON 实现 [json.Marshaler] 接口
+//
+// 不包含子项，如果需要输出子项，可以使用 [Region.WithItems]。
+func (r *Region) MarshalJSON() ([]byte, error) { return json.Marshal(r.toJSON(0)) }
+
+// WithItems 返回包含子项的 [json.Marshaler] 对象
+//
+// depth 为子项的层数，0 表示不包含子项，小于 0 表示包含所有的子项。
+func (r *Region) WithItems(depth int) json.Marshaler { return regionItems{r: r, depth: depth} }
+
+func (ri regionItems) MarshalJSON() ([]byte, error) { return json.Marshal(ri.r.toJSON(ri.depth)) }
+
+func (r *Region) toJSON(depth int) *regionJSON {
+	j := &regionJSON{
+		ID:       r.id,
+		FullID:   r.fullID,
+		Name:     r.name,
+		FullName: r.fullName,
+		Level:    r.level,
+		Versions: r.versions,
+	}
+
+	if depth != 0 {
+		items := r.Items()
+		j.Items = make([]*regionJSON, 0, len(items))
+		for _, item := range items {
+			j.Items = append(j.Items, item.toJSON(depth-1))
+		}
+	}
+
+	return j
+}
 
 // IsSupported 当前数据是否支持该年份
 func (r *Region) IsSupported(ver int) bool { return slices.Index(r.versions, ver) > -1 }
 
-func (reg *Region) addItem(id, name string, level id.Level, ver int) error {
+// 是否支持 year 年份的数据
+//
+// year 为 0 表示不限制年份，根元素始终返回 true。
+func (reg *Region) isSupportedAt(year int) bool {
+	return year == 0 || reg.level == 0 || reg.IsSupported(year)
+}
+
+func (reg *Region) addItem(regionID, name string, level id.Level, ver int) error {
 	if slices.Index(reg.db.versions, ver) == -1 {
-		return fmt.Errorf("不支持该年份 %d 的数据", ver)
+		return fmt.Errorf("%w：%d", ErrUnknownVersion, ver)
 	}
 
-	for _, item := range reg.items {
-		if item.id == id {
-			return fmt.Errorf("已经存在相同 ID 的数据项：%s", id)
+	for _, item := range reg.Items() {
+		if item.id == regionID {
+			return fmt.Errorf("已经存在相同 ID 的数据项：%s", regionID)
 		}
 	}
 
-	reg.items = append(reg.items, &Region{
-		id:       id,
+	fullName, prefix := name, ""
+	if reg.level != 0 { // level == 0 只有根元素才有
+		fullName = reg.fullName + reg.db.fullNameSeparator + name
+		prefix = id.Prefix(reg.fullID)
+	}
+
+	item := &Region{
+		id:       regionID,
 		name:     name,
 		db:       reg.db,
 		level:    level,
 		versions: []int{ver},
-	})
+		fullName: fullName,
+		fullID:   id.Fill(prefix+regionID, id.Village),
+		parent:   reg,
+	}
+	item.loaded.Store(true) // 新添加的区域在二进制数据中没有对应的节点，不需要解码。
+	reg.items = append(reg.items, item)
 	return nil
 }
 
 func (reg *Region) setSupported(ver int) error {
 	if slices.Index(reg.db.versions, ver) == -1 {
-		return fmt.Errorf("不存在该年份 %d 的数据", ver)
+		return fmt.Errorf("%w：%d", ErrUnknownVersion, ver)
 	}
 
 	if !reg.IsSupported(ver) {
@@ -81,7 +215,7 @@ func (reg *Region) findItem(regionID ...string) *Region {
 		return reg
 	}
 
-	for _, item := range reg.items {
+	for _, item := range reg.Items() {
 		if item.id == regionID[0] {
 			return item.findItem(regionID[1:]...)
 		}
@@ -90,17 +224,37 @@ func (reg *Region) findItem(regionID ...string) *Region {
 	return nil
 }
 
-func (reg *Region) marshal(buf *errwrap.Buffer) error {
-	supported := 0
-	for _, ver := range reg.versions {
-		index := slices.Index(reg.db.versions, ver)
-		if index == -1 {
-			return fmt.Errorf("无效的年份 %d 位于 %s", ver, reg.fullName)
+func (reg *Region) walk(level id.Level, fn func(*Region) bool) bool {
+	if reg.level != 0 && reg.level&level == reg.level && !fn(reg) { // level == 0 只有根元素才有
+		return false
+	}
+
+	if reg.level != 0 && level&(reg.level-1) == 0 { // 不需要更低级别的数据
+		return true
+	}
+
+	for _, item := range reg.Items() {
+		if !item.walk(level, fn) {
+			return false
 		}
-		supported += 1 << index
 	}
-	buf.Printf("%s:%s:%d:%d{", reg.id, reg.name, supported, len(reg.items))
-	for _, item := range reg.items {
+	return true
+}
+
+func (reg *Region) marshal(buf *errwrap.Buffer) error {
+	supported, err := reg.versionsMask(reg.versions)
+	if err != nil {
+		return err
+	}
+
+	name, err := reg.marshalNames()
+	if err != nil {
+		return err
+	}
+
+	items := reg.Items()
+	buf.Printf("%s:%s:%d:%d{", reg.id, name, supported, len(items))
+	for _, item := range items {
 		err := item.marshal(buf)
 		if err != nil {
 			return err
@@ -111,90 +265,259 @@ func (reg *Region) marshal(buf *errwrap.Buffer) error {
 	return nil
 }
 
-func (reg *Region) unmarshal(data []byte, parentName, parentID string, level id.Level) error {
+func (reg *Region) unmarshal(d *decoder, parentName, parentID string, level id.Level) (err error) {
 	reg.level = level
 
-	data, reg.id = indexBytes(data, ':')
+	if reg.id, err = d.field(':', parentID); err != nil {
+		return err
+	}
+	if !isValidID(reg.id, parentID, level) {
+		return d.error("区域 ID", parentID, nil)
+	}
+	parentID += reg.id
+	if level != 0 {
+		reg.fullID = id.Fill(parentID, id.Village)
+	}
 
-	data, reg.name = indexBytes(data, ':')
+	if level == id.Province && !reg.db.isProvinceLoaded(reg.id) { // reg.versions 为空，不会被添加到上一级。
+		return d.skip(parentID, level, false)
+	}
+
+	name, err := d.field(':', parentID)
+	if err != nil {
+		return err
+	}
+	nameOffset := d.start
+
+	// Versions
+	supported, err := d.int(':', parentID)
+	if err != nil {
+		return err
+	}
+	reg.versions = reg.db.filterVersions(reg.maskVersions(supported))
+
+	if err := reg.unmarshalNames(name); err != nil {
+		return &SyntaxError{Offset: nameOffset, Expected: "名称", Path: parentID, Err: err}
+	}
 	reg.fullName = reg.name
 	if parentName != "" {
 		reg.fullName = parentName + reg.db.fullNameSeparator + reg.name
 	}
-	parentID += reg.id
-	reg.fullID = id.Fill(parentID, id.Village)
 
-	// Versions
-	data, val := indexBytes(data, ':')
-	supported, err := strconv.Atoi(val)
+	size, err := d.int('{', parentID)
 	if err != nil {
 		return err
 	}
+	if size < 0 || (size > 0 && level == id.Village) { // 村级区域不存在子项
+		return d.error("子项数量", parentID, nil)
+	}
+
+	// 下一级的 Level
+	var next id.Level
+	if level == 0 {
+		next = id.Province
+	} else {
+		next = level >> 1
+	}
+
+	if !reg.db.isLevelLoaded(next) { // 子项都不需要加载
+		for i := 0; i < size; i++ {
+			if err := d.skip(parentID, next, true); err != nil {
+				return err
+			}
+		}
+		return d.expect('}', parentID)
+	}
+
+	for i := 0; i < size; i++ {
+		item := &Region{db: reg.db, parent: reg}
+		if err := item.unmarshal(d, reg.fullName, parentID, next); err != nil {
+			return err
+		}
+		if len(item.versions) > 0 { // 表示该条数据不支持所有的年份
+			reg.items = append(reg.items, item)
+		}
+	}
+
+	return d.expect('}', parentID)
+}
+
+// 判断 regionID 是否为 level 级别的有效 ID
+//
+// parentID 为上一级区域的 ID，不包含后缀的 0。
+func isValidID(regionID, parentID string, level id.Level) bool {
+	if level == 0 {
+		return regionID == ""
+	}
+
+	if len(parentID)+len(regionID) != id.Length(level) {
+		return false
+	}
+	for _, c := range regionID {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// 将年份列表转换为以 db.versions 索引表示的位掩码
+func (reg *Region) versionsMask(versions []int) (int, error) {
+	mask := 0
+	for _, ver := range versions {
+		index := slices.Index(reg.db.versions, ver)
+		if index == -1 {
+			return 0, fmt.Errorf("无效的年份 %d 位于 %s", ver, reg.fullName)
+		}
+		mask += 1 << index
+	}
+	return mask, nil
+}
+
+// 将以 db.versions 索引表示的位掩码转换为年份列表
+func (reg *Region) maskVersions(mask int) []int {
 	versions := make([]int, 0, len(reg.db.versions))
 	for i, v := range reg.db.versions {
-		if flag := 1 << i; flag&supported == flag {
+		if flag := 1 << i; flag&mask == flag {
 			versions = append(versions, v)
 		}
 	}
-	reg.versions = reg.db.filterVersions(versions)
+	return versions
+}
 
-	data, val = indexBytes(data, '{')
-	size, err := strconv.Atoi(val)
+// 数据文件的解码器
+type decoder struct {
+	r       *bufio.Reader
+	buf     []byte
+	pos     int  // 下一个需要读取的字节位置
+	start   int  // 最近一次读取的字段的起始位置
+	escaped bool // 数据中是否包含转义字符，版本 3 之后的数据格式会对名称进行转义。
+}
+
+func newDecoder(r io.Reader) *decoder {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &decoder{r: br, buf: make([]byte, 0, 100)}
+}
+
+// 读取直到 sep 之前的内容
+//
+// 返回的内容并不会去掉转义字符。
+// path 为当前区域的 ID，仅用于生成错误信息。
+func (d *decoder) field(sep byte, path string) (string, error) {
+	val, err := d.read(sep, path)
 	if err != nil {
-		return err
+		return "", err
 	}
+	return string(val), nil
+}
 
-	if size > 0 {
-		for i := 0; i < size; i++ {
-			index := findEnd(data)
-			if index < 0 {
-				return errors.New("未找到结束符号 }")
-			}
+// 与 field 相同，但是返回的内容仅在下一次读取之前有效。
+func (d *decoder) read(sep byte, path string) ([]byte, error) {
+	d.start = d.pos
+	d.buf = d.buf[:0]
+	for {
+		chunk, err := d.r.ReadSlice(sep)
+		d.pos += len(chunk)
+		d.buf = append(d.buf, chunk...)
+
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case errors.Is(err, io.EOF):
+			return nil, &SyntaxError{Offset: d.pos, Expected: strconv.QuoteRune(rune(sep)), Path: path}
+		case err != nil:
+			return nil, err
+		}
 
-			// 下一级的 Level
-			var next id.Level
-			if level == 0 {
-				next = id.Province
-			} else {
-				next = level >> 1
-			}
+		// 非转义的 sep 必然是第一个出现的 sep，即 ReadSlice 返回的最后一个字符。
+		if !d.escaped || indexUnescaped(d.buf, sep) == len(d.buf)-1 {
+			return d.buf[:len(d.buf)-1], nil
+		}
+	}
+}
 
-			item := &Region{db: reg.db}
-			if err := item.unmarshal(data[:index], reg.fullName, parentID, next); err != nil {
-				return err
-			}
-			if len(item.versions) > 0 { // 表示该条数据不支持所有的年份
-				reg.items = append(reg.items, item)
-			}
-			data = data[index+1:]
+// 读取直到 sep 之前的内容并转换为整数
+func (d *decoder) int(sep byte, path string) (int, error) {
+	val, err := d.field(sep, path)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, d.error("整数", path, err)
+	}
+	return v, nil
+}
+
+// 跳过 level 级别的整个区域，包括其子项。
+//
+// withID 表示是否需要读取 ID，为 false 表示 ID 已经被读取。
+func (d *decoder) skip(path string, level id.Level, withID bool) error {
+	if withID {
+		if _, err := d.read(':', path); err != nil {
+			return err
 		}
 	}
 
-	return nil
+	// name 和 versions
+	for i := 0; i < 2; i++ {
+		if _, err := d.read(':', path); err != nil {
+			return err
+		}
+	}
+
+	size, err := d.int('{', path)
+	if err != nil {
+		return err
+	}
+	if size < 0 || (size > 0 && level == id.Village) { // 村级区域不存在子项
+		return d.error("子项数量", path, nil)
+	}
+
+	for i := 0; i < size; i++ {
+		if err := d.skip(path, level>>1, true); err != nil {
+			return err
+		}
+	}
+
+	return d.expect('}', path)
 }
 
-func indexBytes(data []byte, b byte) ([]byte, string) {
-	index := bytes.IndexByte(data, b)
-	if index == -1 {
-		panic(fmt.Sprintf("在%s未找到：%s", string(data), string(b)))
+// 下一个字节必须为 b
+func (d *decoder) expect(b byte, path string) error {
+	c, err := d.r.ReadByte()
+	switch {
+	case errors.Is(err, io.EOF) || (err == nil && c != b):
+		return &SyntaxError{Offset: d.pos, Expected: strconv.QuoteRune(rune(b)), Path: path}
+	case err != nil:
+		return err
 	}
 
-	return data[index+1:], string(data[:index])
+	d.pos++
+	return nil
 }
 
-func findEnd(data []byte) int {
-	deep := 0
-	for i, b := range data {
-		switch b {
-		case '{':
-			deep++
-		case '}':
-			deep--
-			if deep == 0 {
-				return i
-			}
+// 剩余的内容只能是空白字符
+func (d *decoder) end() error {
+	for {
+		r, size, err := d.r.ReadRune()
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		case !unicode.IsSpace(r):
+			return &SyntaxError{Offset: d.pos, Expected: "文件结尾"}
 		}
+		d.pos += size
 	}
+}
 
-	return 0
+// 生成最近一次读取的字段的错误信息
+func (d *decoder) error(expected, path string, err error) error {
+	return &SyntaxError{Offset: d.start, Expected: expected, Path: path, Err: err}
 }
@@ -5,6 +5,7 @@
 package cnregion
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/issue9/assert/v4"
@@ -26,13 +27,71 @@ func TestRegion_IsSupported(t *testing.T) {
 	a.False(obj.root.items[0].IsSupported(2009)) // 不存在于 db
 }
 
+func TestRegion_Parent(t *testing.T) {
+	a := assert.New(t, false)
+
+	db, err := Load(data, "-", false)
+	a.NotError(err).NotNil(db)
+
+	r := db.Find("340100000000")
+	a.NotNil(r).
+		Equal(r.Parent().FullID(), "340000000000").
+		Nil(r.Parent().Parent())
+
+	a.Equal(r.Ancestors(), []*Region{r.Parent()}).
+		Equal(r.Path(), []*Region{r.Parent(), r})
+
+	p := db.Find("340000000000")
+	a.Empty(p.Ancestors()).
+		Equal(p.Path(), []*Region{p})
+
+	for _, d := range db.Districts() {
+		a.Nil(d.Parent())
+	}
+}
+
+func TestRegion_Pinyin(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(obj.Find("340100000000").Pinyin(), "hefei").
+		Equal(obj.Find("340300000000").Pinyin(), "wuhu-2")
+}
+
+func TestRegion_MarshalJSON(t *testing.T) {
+	a := assert.New(t, false)
+
+	db, err := Load(data, "-", false)
+	a.NotError(err).NotNil(db)
+
+	r := db.Find("340000000000")
+	a.NotNil(r)
+
+	b, err := json.Marshal(r)
+	a.NotError(err).
+		Equal(string(b), `{"id":"34","fullID":"340000000000","name":"安徽","fullName":"安徽","level":"province","versions":[2020]}`)
+
+	b, err = json.Marshal([]*Region{r.Items()[0]})
+	a.NotError(err).
+		Equal(string(b), `[{"id":"01","fullID":"340100000000","name":"合肥","fullName":"安徽-合肥","level":"city","versions":[2020,2019]}]`)
+
+	b, err = json.Marshal(db.Find("330000000000").WithItems(1))
+	a.NotError(err).
+		Equal(string(b), `{"id":"33","fullID":"330000000000","name":"浙江","fullName":"浙江","level":"province","versions":[2020],"items":[{"id":"01","fullID":"330100000000","name":"温州","fullName":"浙江-温州","level":"city","versions":[2020,2019]}]}`)
+
+	b2, err := json.Marshal(db.Find("330000000000").WithItems(-1))
+	a.NotError(err).Equal(b2, b)
+
+	b, err = json.Marshal(db.Find("330000000000").WithItems(0))
+	a.NotError(err).NotContains(string(b), "items")
+}
+
 func TestRegion_addItem(t *testing.T) {
 	a := assert.New(t, false)
 
 	obj := &DB{versions: []int{2020, 2019, 2018}}
 	obj.root = &Region{items: []*Region{}, db: obj}
 
-	a.ErrorString(obj.root.addItem("33", "浙江", id.Province, 2001), "不支持该年份")
+	a.ErrorIs(obj.root.addItem("33", "浙江", id.Province, 2001), ErrUnknownVersion)
 
 	a.NotError(obj.root.addItem("44", "广东", id.Province, 2020))
 	a.Equal(obj.root.items[0].id, "44").
@@ -57,11 +116,17 @@ func TestRegion_SetSupported(t *testing.T) {
 	a.ErrorString(obj.root.items[0].setSupported(2001), "不存在该年份")
 }
 
-func TestFindEnd(t *testing.T) {
+func TestIsValidID(t *testing.T) {
 	a := assert.New(t, false)
 
-	data := []byte("0123{56}")
-	a.Equal(findEnd(data), 7)
+	a.True(isValidID("", "", 0)).
+		False(isValidID("33", "", 0)).
+		True(isValidID("33", "", id.Province)).
+		False(isValidID("3", "", id.Province)).
+		True(isValidID("01", "33", id.City)).
+		False(isValidID("0x", "33", id.City)).
+		True(isValidID("001", "330102", id.Town)).
+		False(isValidID("01", "330102", id.Town))
 }
 
 func TestDB_Provinces(t *testing.T) {
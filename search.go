@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/issue9/cnregion/v2/id"
+	"github.com/issue9/cnregion/v2/pinyin"
 )
 
 // Options 搜索选项
@@ -16,6 +17,8 @@ type Options struct {
 	//
 	// 不能是多个名称的组合，比如"浙江温州"，直接写"温州"就可以。
 	// 也不要提供类似于"居委会"这种无实际意义的地名；
+	// 如果需要支持以上两种情况，可以指定 Normalize；
+	// 如果需要同时得到每一级的匹配结果，可以使用 [DB.SearchPath]。
 	Text string
 
 	// 上一级的区域 ID
@@ -30,8 +33,41 @@ type Options struct {
 	Level id.Level
 
 	// 最大的搜索数量。0 表示不限制数量。
-	Max       int
+	Max int
+
+	// Text 的匹配方式
+	Mode Mode
+
+	// 忽略表示行政区划的后缀
+	//
+	// 为 true 时，Text 和区域名称都会采用 [Region.ShortName] 的规则去掉后缀之后再比较，
+	// 比如温州市可以匹配温州地区；Text 也可以是多级区域名称的组合，
+	// 比如杭州市西湖区或是杭州西湖，此时仅匹配最后一级的区域。
+	Normalize bool
+}
+
+// Mode 搜索时 [Options.Text] 的匹配方式
+type Mode int8
+
+const (
+	MatchName   Mode = iota // 仅匹配名称，默认值。
+	MatchPinyin             // 匹配名称、名称的全拼以及拼音首字母，拼音不区分大小写，比如 wenzhou、wz 和 WZ 都可以匹配温州。
+)
+
+// 搜索过程中的状态
+//
+// 与 [Options] 分开保存，以保证 [Options] 在搜索过程中不会被修改，
+// 同一个 [Options] 可以在多个协程中同时使用。
+type searcher struct {
+	text  string
+	py    string // 用于匹配拼音的 text，仅在 MatchPinyin 模式下有值。
+	short string // text 的简称，仅在 Normalize 为 true 时有值。
+	level id.Level
+	year  int // 仅搜索该年份的数据，0 表示不限制。
+	max   int // 剩余的数量，仅在 unlimited 为 false 时有效。
+
 	unlimited bool
+	list      []*Region
 }
 
 func (o *Options) isEmpty() bool {
@@ -42,47 +78,110 @@ func (o *Options) isEmpty() bool {
 }
 
 // Search 简单的搜索功能
-func (db *DB) Search(opt *Options) []*Region {
+//
+// 不会修改 opt 的内容，可以在多个协程中使用同一个 opt。
+func (db *DB) Search(opt *Options) []*Region { return db.search(opt, 0) }
+
+// year 表示仅搜索该年份的数据，0 表示不限制。
+func (db *DB) search(opt *Options, year int) []*Region {
 	if opt == nil || opt.isEmpty() {
 		panic("参数 opt 不能为空值")
 	}
 
+	r, s := db.newSearcher(opt, year)
+	if r == nil {
+		return nil
+	}
+
+	size := 100
+	if !s.unlimited {
+		size = s.max
+	}
+	s.list = make([]*Region, 0, size)
+
+	if db.index != nil && s.text != "" && s.py == "" && s.short == "" {
+		db.index.search(db, r, s)
+	} else {
+		r.search(s)
+	}
+	return s.list
+}
+
+// 根据 opt 生成搜索的起点和状态
+//
+// 如果 opt.Parent 指定的区域不存在，返回的 *Region 为 nil。
+func (db *DB) newSearcher(opt *Options, year int) (*Region, *searcher) {
 	r := db.root
 	if opt.Parent != "" {
 		r = db.Find(opt.Parent)
 	}
-	if r == nil { // 不存在 opt.Parent 指定的数据
-		return nil
+	if r == nil || !r.isSupportedAt(year) { // 不存在 opt.Parent 指定的数据
+		return nil, nil
+	}
+
+	s := &searcher{
+		text:      opt.Text,
+		level:     opt.Level,
+		year:      year,
+		max:       opt.Max,
+		unlimited: opt.Max == 0,
+	}
+	if s.level == 0 {
+		s.level = id.AllLevel
+	}
+	if opt.Normalize {
+		s.short = shortName(opt.Text)
 	}
+	if opt.Mode == MatchPinyin {
+		s.py = strings.ToLower(strings.ReplaceAll(opt.Text, " ", ""))
+	}
+	return r, s
+}
 
-	if opt.Level == 0 {
-		opt.Level = id.AllLevel
+func (reg *Region) search(s *searcher) {
+	if !s.unlimited && s.max <= 0 {
+		return
 	}
 
-	opt.unlimited = opt.Max == 0
-	size := 100
-	if !opt.unlimited {
-		size = opt.Max
+	if !reg.isSupportedAt(s.year) { // 上一级不支持该年份，子项也不可能支持。
+		return
+	}
+
+	if reg.level != 0 && (reg.level&s.level == reg.level) && s.match(reg) { // level == 0 只有根元素才有
+		s.list = append(s.list, reg)
+		s.max--
 	}
-	list := make([]*Region, 0, size)
 
-	return r.search(opt, list)
+	if !s.unlimited && s.max <= 0 {
+		return
+	}
+
+	for _, item := range reg.Items() {
+		item.search(s)
+	}
 }
 
-func (reg *Region) search(opt *Options, list []*Region) []*Region {
-	if strings.Contains(reg.name, opt.Text) &&
-		(reg.level&opt.Level == reg.level) && reg.level != 0 { // level == 0 只有根元素才有
-		list = append(list, reg)
-		opt.Max--
+func (s *searcher) match(reg *Region) bool {
+	name := reg.searchName(s.year)
+	if strings.Contains(name, s.text) {
+		return true
 	}
 
-	if !opt.unlimited && opt.Max <= 0 {
-		return list
+	if s.short != "" {
+		short := shortName(name)
+		if strings.Contains(short, s.short) {
+			return true
+		}
+
+		// 仅在 text 包含当前区域的简称时才需要匹配整个路径
+		if strings.Contains(s.text, short) && matchPath(s.text, reg.Path(), s.year) {
+			return true
+		}
 	}
 
-	for _, item := range reg.items {
-		list = item.search(opt, list)
+	if s.py == "" {
+		return false
 	}
 
-	return list
+	return strings.Contains(pinyin.Full(name), s.py) || strings.Contains(pinyin.Initials(name), s.py)
 }
@@ -14,6 +14,36 @@ import (
 	"github.com/issue9/cnregion/v2/id"
 )
 
+// 供各类搜索功能测试的数据
+//
+// 鼓楼区在福州市和南京市下各有一个，南京市下的仅在 2020 年份中存在。
+func newSearchDB(a *assert.Assertion) *DB {
+	db := NewDB(WithSeparator("-"))
+	a.True(db.AddVersion(2020)).True(db.AddVersion(2019))
+
+	for _, year := range []int{2019, 2020} {
+		a.NotError(db.AddItem("330000000000", "浙江省", year)).
+			NotError(db.AddItem("330300000000", "温州市", year)).
+			NotError(db.AddItem("330302000000", "鹿城区", year)).
+			NotError(db.AddItem("330302001000", "五马街道", year)).
+			NotError(db.AddItem("330302001001", "西湖村", year)).
+			NotError(db.AddItem("330100000000", "杭州市", year)).
+			NotError(db.AddItem("330106000000", "西湖区", year)).
+			NotError(db.AddItem("330106001000", "北山街道", year)).
+			NotError(db.AddItem("330106001001", "小西湖社区", year)).
+			NotError(db.AddItem("350000000000", "福建省", year)).
+			NotError(db.AddItem("350100000000", "福州市", year)).
+			NotError(db.AddItem("350102000000", "鼓楼区", year)).
+			NotError(db.AddItem("320000000000", "江苏省", year)).
+			NotError(db.AddItem("320100000000", "南京市", year)).
+			NotError(db.AddItem("650000000000", "新疆维吾尔自治区", year)).
+			NotError(db.AddItem("650100000000", "乌鲁木齐市", year))
+	}
+	a.NotError(db.AddItem("320106000000", "鼓楼区", 2020))
+
+	return db
+}
+
 func TestDB_Search(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -65,6 +95,51 @@ func TestDB_Search(t *testing.T) {
 	// 只有 Level
 	rs = obj.Search(&Options{Level: id.City + id.Province})
 	a.Equal(6, len(rs))
+
+	// 拼音
+	rs = obj.Search(&Options{Text: "hefei"})
+	a.Empty(rs)
+	rs = obj.Search(&Options{Text: "hefei", Mode: MatchPinyin})
+	a.Equal(1, len(rs)).Equal(rs[0].name, "合肥")
+	rs = obj.Search(&Options{Text: "HF", Mode: MatchPinyin})
+	a.Equal(1, len(rs)).Equal(rs[0].name, "合肥")
+	rs = obj.Search(&Options{Text: "wu hu", Mode: MatchPinyin})
+	a.Equal(2, len(rs))
+	rs = obj.Search(&Options{Text: "合", Mode: MatchPinyin})
+	a.Equal(1, len(rs))
+
+	// 忽略后缀
+	db := newNamesDB(a)
+	a.NotError(db.AddItem("330302", "鹿城区", 2020)).
+		NotError(db.AddItem("3301", "杭州市", 2020)).
+		NotError(db.AddItem("330106", "西湖区", 2020)).
+		NotError(db.AddItem("330106001", "北山街道", 2020)).
+		NotError(db.AddItem("330106001001", "西湖社区居委会", 2020))
+	rs = db.Search(&Options{Text: "温州地区"})
+	a.Empty(rs)
+	rs = db.Search(&Options{Text: "温州地区", Normalize: true})
+	a.Equal(1, len(rs)).Equal(rs[0].name, "温州市")
+	rs = db.Search(&Options{Text: "洞头县", Normalize: true})
+	a.Equal(1, len(rs)).Equal(rs[0].name, "洞头区")
+	rs = db.Search(&Options{Text: "杭州市西湖区"})
+	a.Empty(rs)
+	rs = db.Search(&Options{Text: "杭州市西湖区", Normalize: true})
+	a.Equal(1, len(rs)).Equal(rs[0].fullID, "330106000000")
+	rs = db.Search(&Options{Text: "杭州西湖", Normalize: true})
+	a.Equal(1, len(rs)).Equal(rs[0].fullID, "330106000000")
+	rs = db.Search(&Options{Text: "西湖", Normalize: true})
+	a.Equal(2, len(rs))
+	rs = db.Search(&Options{Text: "西湖居委会", Normalize: true})
+	a.Equal(2, len(rs))
+	rs = db.Search(&Options{Text: "北山街道西湖社区", Normalize: true, Level: id.Village})
+	a.Equal(1, len(rs)).Equal(rs[0].fullID, "330106001001")
+
+	// Max 限制了数量，且不会修改 opt
+	opt := &Options{Level: id.City, Parent: "340000000000", Max: 1}
+	rs = obj.Search(opt)
+	a.Equal(1, len(rs)).
+		Equal(rs[0].name, "合肥").
+		Equal(opt, &Options{Level: id.City, Parent: "340000000000", Max: 1})
 }
 
 func TestDB_SearchWithData(t *testing.T) {
@@ -79,3 +154,32 @@ func TestDB_SearchWithData(t *testing.T) {
 	got = obj.Search(&Options{Text: "温州", Level: id.Province})
 	a.Empty(got)
 }
+
+func BenchmarkDB_Search(b *testing.B) {
+	a := assert.New(b, false)
+
+	scan, err := Open("./data/regions.db", WithSeparator("-"))
+	a.NotError(err).NotNil(scan)
+	indexed, err := Open("./data/regions.db", WithSeparator("-"), WithSearchIndex())
+	a.NotError(err).NotNil(indexed)
+
+	opts := map[string]*Options{
+		"village": {Text: "居委会", Level: id.Village, Max: 10},
+		"rare":    {Text: "洞头"},
+		"parent":  {Text: "村", Parent: "330300000000", Max: 20},
+	}
+
+	for name, opt := range opts {
+		b.Run(name+"/scan", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				scan.Search(opt)
+			}
+		})
+
+		b.Run(name+"/index", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				indexed.Search(opt)
+			}
+		})
+	}
+}
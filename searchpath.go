@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 匹配时最多可以连续省略的级别数量
+//
+// 比如温州鹿城省略了省级，鹿城五马省略了省级和市级。
+const maxPathSkip = 2
+
+// 计算 [PathMatch.Score] 时的权重
+const (
+	pathRuneScore = 10 // 每匹配一个字符的得分
+	pathSkipScore = 5  // 每省略一个级别扣除的分数
+)
+
+// PathMatch [DB.SearchPath] 返回的候选结果
+type PathMatch struct {
+	// 匹配的最后一级区域
+	//
+	// 完整的路径可以通过 [Region.Path] 获取。
+	Region *Region
+
+	// 匹配度，值越大越匹配
+	//
+	// 每匹配一个字符加 10 分，每省略一个级别扣 5 分。
+	Score int
+
+	// 查询内容中未能匹配的部分
+	//
+	// 比如浙江温州鹿城区五马街 128 号中的五马街 128 号。
+	Rest string
+}
+
+// 多级搜索过程中的状态
+type pathSearcher struct {
+	year    int
+	matches map[*Region]*PathMatch
+}
+
+// SearchPath 将 text 拆分为多级区域的名称进行搜索
+//
+// text 由从上至下连续多级区域的名称组成，每一级可以是名称、简称或是简称加任意后缀，
+// 中间可以包含空格和标点，也可以省略部分级别，比如浙江温州鹿城、
+// 浙江省 温州市 鹿城区以及温州鹿城都可以匹配浙江省温州市鹿城区。
+// 末尾无法匹配的内容保存在 [PathMatch.Rest] 之中，可以是街道门牌等详细地址。
+//
+// 对于有歧义的内容会返回所有可能的结果，按 [PathMatch.Score] 从高到低排列，
+// 分数相同的按区域 ID 排列。max 表示返回的最大数量，0 表示不限制。
+func (db *DB) SearchPath(text string, max int) []*PathMatch {
+	return db.searchPath(text, max, 0)
+}
+
+// [DB.SearchPath] 和 [View.SearchPath] 的实现，year 为 0 时不检查区域的年份。
+func (db *DB) searchPath(text string, max, year int) []*PathMatch {
+	s := &pathSearcher{year: year, matches: make(map[*Region]*PathMatch, 10)}
+	s.walk(db.root, text, 0)
+
+	// 如果下级区域的匹配度更高，那么上级区域仅是其匹配过程中的一部分，不再作为结果返回。
+	for reg, m := range s.matches {
+		for p := reg.parent; p != nil; p = p.parent {
+			if pm, found := s.matches[p]; found && pm.Score <= m.Score {
+				delete(s.matches, p)
+			}
+		}
+	}
+
+	list := make([]*PathMatch, 0, len(s.matches))
+	for _, m := range s.matches {
+		list = append(list, m)
+	}
+	slices.SortFunc(list, func(a, b *PathMatch) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		return strings.Compare(a.Region.fullID, b.Region.fullID)
+	})
+
+	if max > 0 && len(list) > max {
+		list = list[:max]
+	}
+	return list
+}
+
+// 从 reg 的下级区域中查找与 text 开头部分相匹配的区域
+//
+// score 为从根元素到 reg 的匹配度。
+func (s *pathSearcher) walk(reg *Region, text string, score int) {
+	text = strings.TrimLeftFunc(text, isPathSeparator)
+
+	found := false
+	if text != "" {
+		s.descendants(reg, 0, func(item *Region, skipped int) {
+			for _, n := range segments(text, item.searchName(s.year)) {
+				found = true
+				s.walk(item, text[n:], score+utf8.RuneCountInString(text[:n])*pathRuneScore-skipped*pathSkipScore)
+			}
+		})
+	}
+
+	if !found && reg.level != 0 { // level == 0 只有根元素才有
+		s.add(reg, text, score)
+	}
+}
+
+// 对 reg 之下 maxPathSkip+1 级以内的区域调用 fn
+//
+// skipped 表示 item 与 reg 之间省略的级别数量。
+func (s *pathSearcher) descendants(reg *Region, skipped int, fn func(item *Region, skipped int)) {
+	for _, item := range reg.Items() {
+		if !item.isSupportedAt(s.year) { // item 及其子区域都不能出现在结果之中，也不能被省略。
+			continue
+		}
+
+		fn(item, skipped)
+		if skipped < maxPathSkip {
+			s.descendants(item, skipped+1, fn)
+		}
+	}
+}
+
+func (s *pathSearcher) add(reg *Region, rest string, score int) {
+	if m, found := s.matches[reg]; found && m.Score >= score {
+		return
+	}
+	s.matches[reg] = &PathMatch{Region: reg, Score: score, Rest: rest}
+}
+
+// 各级名称之间允许出现的分隔符
+func isPathSeparator(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) }
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func pathIDs(list []*PathMatch) []string {
+	ids := make([]string, 0, len(list))
+	for _, m := range list {
+		ids = append(ids, m.Region.FullID())
+	}
+	return ids
+}
+
+func TestDB_SearchPath(t *testing.T) {
+	a := assert.New(t, false)
+	db := newSearchDB(a)
+
+	list := db.SearchPath("浙江温州鹿城", 0)
+	a.Length(list, 1).
+		Equal(list[0].Region.FullID(), "330302000000").
+		Equal(list[0].Score, 60).
+		Empty(list[0].Rest)
+
+	// 名称、后缀以及分隔符
+	list = db.SearchPath("浙江省 温州市，鹿城区", 0)
+	a.Length(list, 1).
+		Equal(list[0].Region.FullID(), "330302000000").
+		Equal(list[0].Score, 90).
+		Empty(list[0].Rest)
+
+	// 详细地址
+	list = db.SearchPath("浙江省温州市鹿城区五马街道解放街 128 号", 0)
+	a.Length(list, 1).
+		Equal(list[0].Region.FullID(), "330302001000").
+		Equal(list[0].Rest, "解放街 128 号")
+
+	list = db.SearchPath("温州市鹿城区人民路", 0)
+	a.Length(list, 1).
+		Equal(list[0].Region.FullID(), "330302000000").
+		Equal(list[0].Score, 55).
+		Equal(list[0].Rest, "人民路")
+
+	// 省略多个级别
+	list = db.SearchPath("鹿城五马", 0)
+	a.Length(list, 1).
+		Equal(list[0].Region.FullID(), "330302001000").
+		Equal(list[0].Score, 30)
+
+	// 有歧义的内容
+	list = db.SearchPath("鼓楼区", 0)
+	a.Equal(pathIDs(list), []string{"320106000000", "350102000000"})
+	a.Equal(list[0].Score, list[1].Score)
+
+	list = db.SearchPath("福建鼓楼", 0)
+	a.Equal(pathIDs(list), []string{"350102000000"})
+
+	list = db.SearchPath("福州鼓楼", 0)
+	a.Equal(pathIDs(list), []string{"350102000000"})
+
+	// 匹配度更高的排在前面
+	list = db.SearchPath("江苏鼓楼", 0)
+	a.Equal(pathIDs(list), []string{"320106000000"}).
+		Equal(list[0].Score, 35)
+
+	list = db.SearchPath("江苏南京鼓楼", 0)
+	a.Equal(pathIDs(list), []string{"320106000000"}).
+		Equal(list[0].Score, 60)
+
+	list = db.SearchPath("南京市西湖", 0)
+	a.Equal(pathIDs(list), []string{"320100000000"}).
+		Equal(list[0].Rest, "西湖")
+
+	// max
+	list = db.SearchPath("鼓楼", 1)
+	a.Equal(pathIDs(list), []string{"320106000000"})
+
+	a.Empty(db.SearchPath("北京", 0)).
+		Empty(db.SearchPath("", 0)).
+		Empty(db.SearchPath("温州鹿城", 0)[0].Rest)
+}
+
+func TestView_SearchPath(t *testing.T) {
+	a := assert.New(t, false)
+	db := newSearchDB(a)
+
+	a.Equal(pathIDs(db.At(2019).SearchPath("鼓楼区", 0)), []string{"350102000000"}).
+		Equal(pathIDs(db.At(2020).SearchPath("鼓楼区", 0)), []string{"320106000000", "350102000000"}).
+		Equal(pathIDs(db.At(2019).SearchPath("江苏南京鼓楼", 0)), []string{"320100000000"}).
+		Empty(db.At(2001).SearchPath("鼓楼区", 0))
+}
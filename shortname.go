@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// 无法通过去掉后缀得到的简称
+var abbreviations = map[string]string{
+	"内蒙古自治区":      "内蒙古",
+	"广西壮族自治区":     "广西",
+	"西藏自治区":       "西藏",
+	"宁夏回族自治区":     "宁夏",
+	"新疆维吾尔自治区":    "新疆",
+	"新疆生产建设兵团":    "兵团",
+	"香港特别行政区":     "香港",
+	"澳门特别行政区":     "澳门",
+	"省直辖县级行政区划":   "省直辖县",
+	"自治区直辖县级行政区划": "自治区直辖县",
+}
+
+// 表示行政区划的后缀
+//
+// 同一后缀的较长形式必须在较短形式之前，比如社区居民委员会必须在社区之前。
+var suffixes = []string{
+	"社区居民委员会", "社区居委会", "居民委员会", "村民委员会",
+	"街道办事处", "村委会", "居委会",
+	"自治州", "自治县", "自治旗", "自治区", "特别行政区",
+	"地区", "新区", "林区", "特区", "街道", "社区",
+	"省", "市", "县", "区", "旗", "盟", "镇", "乡", "村",
+}
+
+// 自治地方名称中的民族名称，按长度由长到短排列。
+var ethnicGroups = []string{
+	"柯尔克孜", "乌孜别克",
+	"维吾尔", "哈萨克", "傈僳", "达斡尔", "东乡", "纳西", "景颇", "土家", "哈尼", "仫佬", "毛南",
+	"仡佬", "锡伯", "阿昌", "普米", "塔吉克", "俄罗斯", "鄂温克", "德昂", "保安", "裕固", "塔塔尔",
+	"独龙", "鄂伦春", "赫哲", "门巴", "珞巴", "基诺", "布依", "朝鲜", "蒙古", "拉祜", "布朗", "撒拉",
+	"高山", "各",
+	"回", "藏", "苗", "彝", "壮", "满", "侗", "瑶", "白", "傣", "黎", "佤", "畲", "水", "土", "羌", "怒", "京",
+}
+
+// ShortName 区域名称的简称
+//
+// 去掉名称中表示行政区划的后缀，比如温州市返回温州，
+// 自治地方还会去掉民族名称，比如延边朝鲜族自治州返回延边；
+// 部分无法通过规则得到的简称会采用习惯的写法，比如广西壮族自治区返回广西。
+// 如果去掉后缀之后只剩下一个字，则返回原名称，比如泾县。
+func (r *Region) ShortName() string { return shortName(r.name) }
+
+func shortName(name string) string {
+	if short, found := abbreviations[name]; found {
+		return short
+	}
+
+	for _, suffix := range suffixes {
+		short, found := strings.CutSuffix(name, suffix)
+		if !found {
+			continue
+		}
+
+		if strings.HasPrefix(suffix, "自治") {
+			short = trimEthnicGroups(short)
+		}
+
+		if utf8.RuneCountInString(short) > 1 {
+			return short
+		}
+		return name
+	}
+
+	return name
+}
+
+// 去掉 name 末尾的民族名称，比如湘西土家族苗族返回湘西。
+func trimEthnicGroups(name string) string {
+LOOP:
+	for {
+		stem, found := strings.CutSuffix(name, "族")
+		if !found {
+			return name
+		}
+
+		for _, group := range ethnicGroups {
+			if s, found := strings.CutSuffix(stem, group); found && utf8.RuneCountInString(s) > 1 {
+				name = s
+				continue LOOP
+			}
+		}
+		return name
+	}
+}
+
+// 判断 text 是否与 path 末尾的连续多个区域相匹配
+//
+// path 为从省级区域开始至当前区域的路径，text 由这些区域的名称或是简称加任意后缀依次拼接而成，
+// 比如杭州市西湖区、杭州西湖和浙江杭州市西湖都与浙江省-杭州市-西湖区相匹配。
+// 各区域采用 year 年份中的名称，0 表示采用最新的名称。
+func matchPath(text string, path []*Region, year int) bool {
+	for start := range path {
+		if consumePath(text, path[start:], year) {
+			return true
+		}
+	}
+	return false
+}
+
+func consumePath(text string, path []*Region, year int) bool {
+	if len(path) == 0 {
+		return text == ""
+	}
+
+	for _, n := range segments(text, path[0].searchName(year)) {
+		if consumePath(text[n:], path[1:], year) {
+			return true
+		}
+	}
+	return false
+}
+
+// 返回 text 中可以作为名称 name 的开头部分的长度
+//
+// 开头部分可以是 name 本身、name 的简称或是简称加上任意后缀，返回值按从长到短排列。
+func segments(text, name string) []int {
+	short := shortName(name)
+	if !strings.HasPrefix(text, short) {
+		return nil
+	}
+
+	var list []int
+	for i := len(text); i >= len(short); i-- {
+		if i < len(text) && !utf8.RuneStart(text[i]) {
+			continue
+		}
+
+		if seg := text[:i]; seg == short || seg == name || shortName(seg) == short {
+			list = append(list, i)
+		}
+	}
+	return list
+}
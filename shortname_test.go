@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestShortName(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := map[string]string{
+		"浙江省":        "浙江",
+		"温州市":        "温州",
+		"温州地区":       "温州",
+		"洞头县":        "洞头",
+		"洞头区":        "洞头",
+		"浦东新区":       "浦东",
+		"神农架林区":      "神农架",
+		"沙市区":        "沙市",
+		"五马街道":       "五马",
+		"五马街道办事处":    "五马",
+		"东屏镇":        "东屏",
+		"城东社区居民委员会":  "城东",
+		"城东社区居委会":    "城东",
+		"城东社区":       "城东",
+		"五一村委会":      "五一",
+		"五一村民委员会":    "五一",
+		"锡林郭勒盟":      "锡林郭勒",
+		"鄂托克旗":       "鄂托克",
+		"内蒙古自治区":     "内蒙古",
+		"广西壮族自治区":    "广西",
+		"延边朝鲜族自治州":   "延边",
+		"湘西土家族苗族自治州": "湘西",
+		"大通回族土族自治县":  "大通",
+		"海西蒙古族藏族自治州": "海西",
+		"积石山保安族东乡族撒拉族自治县": "积石山",
+		"莫力达瓦达斡尔族自治旗":     "莫力达瓦",
+		"沙县":              "沙县",
+		"泾县":              "泾县",
+		"温州":              "温州",
+		"居委会":             "居委会",
+		"":                "",
+	}
+	for name, short := range data {
+		a.Equal(shortName(name), short, name)
+	}
+
+	a.Equal(obj.Find("340100000000").ShortName(), "合肥")
+}
+
+func TestMatchPath(t *testing.T) {
+	a := assert.New(t, false)
+
+	db := newNamesDB(a)
+	path := db.Find("330305000000").Path()
+
+	a.True(matchPath("洞头区", path, 0)).
+		True(matchPath("温州市洞头区", path, 0)).
+		True(matchPath("温州洞头", path, 0)).
+		True(matchPath("浙江温州市洞头", path, 0)).
+		True(matchPath("浙江省温州市洞头区", path, 0)).
+		True(matchPath("温州地区洞头县", path, 0)).
+		False(matchPath("浙江洞头", path, 0)).
+		False(matchPath("温州市", path, 0)).
+		False(matchPath("洞头区温州", path, 0))
+}
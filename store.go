@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import "sync/atomic"
+
+// Store 可在多个协程中共享的 [DB] 容器
+//
+// 通过 [Store.Reload] 加载新的数据并整体替换当前的 [DB] 对象，
+// 替换过程不会阻塞读取操作，已经通过 [Store.DB] 获取的对象也不受影响。
+//
+// 由 [Store.DB] 返回的对象应该被当作只读的，不能调用 [DB.AddItem] 等修改数据的方法。
+type Store struct {
+	db   atomic.Pointer[DB]
+	opts []Option
+}
+
+// NewStore 从 path 加载数据并返回 [Store] 对象
+//
+// opts 同时也用于之后的 [Store.Reload]。
+func NewStore(path string, opts ...Option) (*Store, error) {
+	s := &Store{opts: opts}
+	if err := s.Reload(path); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// DB 返回当前的 [DB] 对象
+func (s *Store) DB() *DB { return s.db.Load() }
+
+// Reload 从 path 加载数据并替换当前的 [DB] 对象
+//
+// 加载失败时返回错误，当前的数据保持不变。
+func (s *Store) Reload(path string) error {
+	db, err := Open(path, s.opts...)
+	if err != nil {
+		return err
+	}
+
+	s.db.Store(db)
+	return nil
+}
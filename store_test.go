@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestStore(t *testing.T) {
+	a := assert.New(t, false)
+	dir := t.TempDir()
+
+	p1 := filepath.Join(dir, "1.db")
+	a.NotError(os.WriteFile(p1, data, os.ModePerm))
+	p2 := filepath.Join(dir, "2.db")
+	a.NotError(newNamesDB(a).Dump(p2, true))
+
+	s, err := NewStore(p1, WithSeparator(">"))
+	a.NotError(err).NotNil(s)
+	db1 := s.DB()
+	a.Equal(db1.Find("340100000000").FullName(), "安徽>合肥")
+
+	a.NotError(s.Reload(p2))
+	a.Equal(s.DB().Find("330305000000").FullName(), "浙江省>温州市>洞头区").
+		Equal(db1.Find("340100000000").FullName(), "安徽>合肥") // 原来的对象不受影响
+
+	// 加载失败
+	a.Error(s.Reload(filepath.Join(dir, "not-exists.db"))).
+		NotNil(s.DB().Find("330305000000"))
+
+	s, err = NewStore(filepath.Join(dir, "not-exists.db"))
+	a.Error(err).Nil(s)
+}
+
+func TestStore_concurrent(t *testing.T) {
+	a := assert.New(t, false)
+	dir := t.TempDir()
+
+	p := filepath.Join(dir, "regions.db")
+	bin, err := newNamesDB(a).MarshalBinary()
+	a.NotError(err).NotError(os.WriteFile(p, bin, os.ModePerm))
+
+	s, err := NewStore(p, WithSeparator("-"))
+	a.NotError(err).NotNil(s)
+
+	opt := &Options{Text: "洞头", Max: 1}
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			db := s.DB()
+			a.Length(db.Search(opt), 1).
+				Length(db.At(2019).Search(opt), 1).
+				NotNil(db.Find("330305000000"))
+		}()
+		go func() {
+			defer wg.Done()
+			a.NotError(s.Reload(p))
+		}()
+	}
+	wg.Wait()
+
+	a.Equal(opt, &Options{Text: "洞头", Max: 1})
+}
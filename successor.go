@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/issue9/cnregion/v2/id"
+)
+
+// 区域的继任者
+type succession struct {
+	to   string // 继任区域的 ID
+	year int    // 从该年份开始由 to 代替
+}
+
+// Successors 获取 fullID 在 year 年份中的继任区域
+//
+// 当区域被撤销、合并或是重新编码之后，原来的 ID 将不再有效，
+// 此方法可以根据 [DB.AddSuccessor]、[DB.LoadSuccessors] 和 [DB.SeedSuccessors]
+// 添加的映射关系找到在 year 年份中代替 fullID 的区域，可用于迁移旧数据中的 ID。
+//
+// 如果 fullID 本身在 year 年份中有效，则返回只包含该区域的列表；
+// 如果找不到任何继任区域，则返回空值。
+func (db *DB) Successors(fullID string, year int) []*Region {
+	return db.successorsOf(fullID, year, make(map[string]struct{}, 5))
+}
+
+func (db *DB) successorsOf(fullID string, year int, visited map[string]struct{}) []*Region {
+	if _, found := visited[fullID]; found { // 防止循环引用
+		return nil
+	}
+	visited[fullID] = struct{}{}
+
+	if len(fullID) == id.Length(id.Village) {
+		if r := db.Find(fullID); r != nil && r.IsSupported(year) {
+			return []*Region{r}
+		}
+	}
+
+	var list []*Region
+	for _, s := range db.successors[fullID] {
+		if s.year > year {
+			continue
+		}
+
+		for _, r := range db.successorsOf(s.to, year, visited) {
+			if slices.Index(list, r) == -1 {
+				list = append(list, r)
+			}
+		}
+	}
+	return list
+}
+
+// AddSuccessor 指定 from 从 year 年份开始由 to 代替
+//
+// 一个区域可以有多个继任区域，比如拆分的情况；
+// 多个区域也可以指向同一个继任区域，比如合并的情况。
+func (db *DB) AddSuccessor(from, to string, year int) error {
+	if len(from) != id.Length(id.Village) {
+		return fmt.Errorf("无效的 ID %s", from)
+	}
+	if len(to) != id.Length(id.Village) {
+		return fmt.Errorf("无效的 ID %s", to)
+	}
+	if from == to {
+		return fmt.Errorf("%s 不能是自身的继任者", from)
+	}
+
+	db.addSuccessor(from, to, year)
+	return nil
+}
+
+// 添加继任关系，返回是否为新添加的关系。
+//
+// 如果关系已经存在，仅在 year 更早时更新其年份。
+func (db *DB) addSuccessor(from, to string, year int) bool {
+	if db.successors == nil {
+		db.successors = make(map[string][]*succession, 100)
+	}
+
+	for _, s := range db.successors[from] {
+		if s.to == to {
+			s.year = min(s.year, year)
+			return false
+		}
+	}
+	db.successors[from] = append(db.successors[from], &succession{to: to, year: year})
+	return true
+}
+
+// LoadSuccessors 从 r 中加载人工整理的继任关系
+//
+// 每一行表示一条记录，格式为：
+//
+//	from	to	year
+//
+// 字段之间以制表符分隔，to 可以是以逗号分隔的多个 ID。
+// 空行以及以 # 开头的行会被忽略。
+func (db *DB) LoadSuccessors(r io.Reader) error {
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		txt := strings.TrimSpace(s.Text())
+		if txt == "" || txt[0] == '#' {
+			continue
+		}
+
+		fields := strings.Split(txt, "\t")
+		if len(fields) != 3 {
+			return fmt.Errorf("无效的格式，位于第 %d 行：%s", line, txt)
+		}
+
+		year, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+		if err != nil {
+			return fmt.Errorf("无效的年份，位于第 %d 行：%w", line, err)
+		}
+
+		from := strings.TrimSpace(fields[0])
+		for _, to := range strings.Split(fields[1], ",") {
+			if err := db.AddSuccessor(from, strings.TrimSpace(to), year); err != nil {
+				return fmt.Errorf("%w，位于第 %d 行", err, line)
+			}
+		}
+	}
+
+	return s.Err()
+}
+
+// SeedSuccessors 根据数据推断继任关系
+//
+// 对于相邻的两个年份，如果某一区域在后一年份中不再存在，
+// 而同一上级区域下在后一年份中新增了同名的区域，则认为后者是前者的继任者。
+// 名称的比较会忽略县、区等行政区划的后缀，比如洞头县和洞头区会被认为是同名的。
+//
+// 返回新添加的继任关系数量。
+func (db *DB) SeedSuccessors() int {
+	versions := slices.Clone(db.versions)
+	slices.Sort(versions)
+
+	count := 0
+	for i := 1; i < len(versions); i++ {
+		prev, curr := versions[i-1], versions[i]
+
+		db.root.walk(id.AllLevel, func(r *Region) bool {
+			if !r.IsSupported(prev) || r.IsSupported(curr) {
+				return true
+			}
+
+			for _, to := range findSuccessors(r, prev, curr) {
+				if db.addSuccessor(r.fullID, to.fullID, curr) {
+					count++
+				}
+			}
+			return true
+		})
+	}
+
+	return count
+}
+
+// 在 r 的同级区域中查找在 curr 年份中新增的同名区域
+func findSuccessors(r *Region, prev, curr int) []*Region {
+	name := r.NameAt(prev)
+	stem := shortName(name)
+
+	var exact, similar []*Region
+	for _, item := range r.parent.Items() {
+		if item == r || item.IsSupported(prev) || !item.IsSupported(curr) {
+			continue
+		}
+
+		switch n := item.NameAt(curr); {
+		case n == name:
+			exact = append(exact, item)
+		case shortName(n) == stem:
+			similar = append(similar, item)
+		}
+	}
+
+	if len(exact) > 0 {
+		return exact
+	}
+	return similar
+}
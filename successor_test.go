@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func newSuccessorDB(a *assert.Assertion) *DB {
+	db := NewDB()
+	a.True(db.AddVersion(2020)).True(db.AddVersion(2019)).True(db.AddVersion(2018))
+
+	for _, year := range []int{2018, 2019, 2020} {
+		a.NotError(db.AddItem("330000000000", "浙江省", year)).
+			NotError(db.AddItem("330300000000", "温州市", year))
+	}
+
+	a.NotError(db.AddItem("330322000000", "洞头县", 2018)).
+		NotError(db.AddItem("330305000000", "洞头区", 2019)).
+		NotError(db.AddItem("330305000000", "洞头区", 2020)).
+		NotError(db.AddItem("330323000000", "甲县", 2018)).
+		NotError(db.AddItem("330323000000", "甲县", 2019)).
+		NotError(db.AddItem("330324000000", "乙县", 2018)).
+		NotError(db.AddItem("330324000000", "乙县", 2019)).
+		NotError(db.AddItem("330326000000", "丙区", 2020))
+
+	return db
+}
+
+func TestDB_SeedSuccessors(t *testing.T) {
+	a := assert.New(t, false)
+	db := newSuccessorDB(a)
+
+	a.Equal(db.SeedSuccessors(), 1).
+		Equal(db.SeedSuccessors(), 0). // 已经存在的关系不再计数
+		Length(db.successors["330322000000"], 1)
+
+	rs := db.Successors("330322000000", 2020)
+	a.Length(rs, 1).Equal(rs[0].FullID(), "330305000000")
+
+	rs = db.Successors("330322000000", 2018)
+	a.Length(rs, 1).Equal(rs[0].FullID(), "330322000000")
+
+	a.Empty(db.Successors("330323000000", 2020)).
+		Empty(db.Successors("990000000000", 2020)).
+		Empty(db.Successors("99", 2020))
+}
+
+func TestDB_LoadSuccessors(t *testing.T) {
+	a := assert.New(t, false)
+	db := newSuccessorDB(a)
+
+	a.NotError(db.LoadSuccessors(strings.NewReader(`# 合并
+330323000000	330326000000	2020
+
+330324000000	330326000000,330305000000	2020
+330322000000	330323000000	2019
+`)))
+
+	rs := db.Successors("330323000000", 2020)
+	a.Length(rs, 1).Equal(rs[0].FullID(), "330326000000")
+
+	rs = db.Successors("330324000000", 2020)
+	a.Length(rs, 2).Equal(rs[0].FullID(), "330326000000").Equal(rs[1].FullID(), "330305000000")
+	a.Empty(db.Successors("330324000000", 2001))
+
+	// 多级继任
+	rs = db.Successors("330322000000", 2019)
+	a.Length(rs, 1).Equal(rs[0].FullID(), "330323000000")
+	rs = db.Successors("330322000000", 2020)
+	a.Length(rs, 1).Equal(rs[0].FullID(), "330326000000")
+
+	a.ErrorString(db.LoadSuccessors(strings.NewReader("330323000000\t330326000000")), "第 1 行")
+	a.ErrorString(db.LoadSuccessors(strings.NewReader("330323000000\t330326000000\tx")), "无效的年份")
+	a.ErrorString(db.LoadSuccessors(strings.NewReader("3303230\t330326000000\t2020")), "无效的 ID")
+}
+
+func TestDB_AddSuccessor(t *testing.T) {
+	a := assert.New(t, false)
+	db := newSuccessorDB(a)
+
+	a.NotError(db.AddSuccessor("330323000000", "330326000000", 2020)).
+		NotError(db.AddSuccessor("330326000000", "330323000000", 2020)) // 循环引用
+	a.Empty(db.Successors("330324000000", 2020)).
+		Length(db.Successors("330323000000", 2020), 1)
+
+	a.ErrorString(db.AddSuccessor("330323000000", "330323000000", 2020), "自身")
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+// View 指定年份的数据视图
+//
+// 仅返回在该年份中有效的区域，即 [Region.IsSupported] 为 true 的数据。
+// 适用于加载了多个年份的 [DB] 对象。
+type View struct {
+	db   *DB
+	year int
+}
+
+// At 返回指定年份的数据视图
+//
+// year 为 0 表示不限制年份，返回的视图包含所有的数据；
+// 其它不在 [DB.Versions] 之中的年份，返回的视图中不会包含任何数据。
+func (db *DB) At(year int) View { return View{db: db, year: year} }
+
+// Year 当前视图对应的年份
+func (v View) Year() int { return v.year }
+
+// Find 查找指定 ID 对应的信息
+//
+// 如果该区域在当前年份中不存在，返回 nil。
+func (v View) Find(regionID string) *Region {
+	if r := v.db.Find(regionID); r != nil && r.isSupportedAt(v.year) {
+		return r
+	}
+	return nil
+}
+
+// Provinces 省份列表
+func (v View) Provinces() []*Region { return v.filter(v.db.Provinces()) }
+
+// Items 返回 r 在当前年份下的子项
+func (v View) Items(r *Region) []*Region { return v.filter(r.Items()) }
+
+// Search 简单的搜索功能
+//
+// 与 [DB.Search] 相同，但仅返回当前年份中有效的数据。
+func (v View) Search(opt *Options) []*Region { return v.db.search(opt, v.year) }
+
+// SearchRanked 按匹配度排序的搜索功能
+//
+// 与 [DB.SearchRanked] 相同，但仅返回当前年份中有效的数据。
+func (v View) SearchRanked(opt *Options) []Match { return v.db.searchRanked(opt, v.year) }
+
+// SearchPath 将 text 拆分为多级区域的名称进行搜索
+//
+// 与 [DB.SearchPath] 相同，但仅返回当前年份中有效的数据。
+func (v View) SearchPath(text string, max int) []*PathMatch {
+	return v.db.searchPath(text, max, v.year)
+}
+
+func (v View) filter(items []*Region) []*Region {
+	list := make([]*Region, 0, len(items))
+	for _, item := range items {
+		if item.isSupportedAt(v.year) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
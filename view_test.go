@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2021-2024 caixw
+//
+// SPDX-License-Identifier: MIT
+
+package cnregion
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v4"
+)
+
+func TestDB_At(t *testing.T) {
+	a := assert.New(t, false)
+
+	db, err := Load([]byte(`1:[2020,2019]:::3:2{33:浙江:3:1{01:温州:3:0{}}34:安徽:1:2{01:合肥:1:0{}02:芜湖:1:0{}}}`), "-", false)
+	a.NotError(err).NotNil(db)
+
+	v := db.At(2019)
+	a.Equal(v.Year(), 2019).
+		Length(v.Provinces(), 1).
+		Equal(v.Provinces()[0].FullID(), "330000000000").
+		NotNil(v.Find("330100000000")).
+		Nil(v.Find("340100000000")).
+		Nil(v.Find("990000000000"))
+	a.Length(v.Search(&Options{Text: "合肥"}), 0).
+		Length(v.Search(&Options{Text: "温州"}), 1).
+		Length(v.Search(&Options{Parent: "340000000000", Text: "湖"}), 0)
+
+	v = db.At(2020)
+	a.Length(v.Provinces(), 2).
+		NotNil(v.Find("340100000000")).
+		Length(v.Items(v.Find("340000000000")), 2).
+		Length(v.Search(&Options{Text: "合肥"}), 1).
+		Length(v.Search(&Options{Parent: "340000000000", Text: "湖"}), 1)
+
+	// 不限制年份
+	v = db.At(0)
+	a.Length(v.Provinces(), 2).
+		NotNil(v.Find("340100000000")).
+		Length(v.Items(v.Find("340000000000")), 2).
+		Length(v.Search(&Options{Text: "合肥"}), 1).
+		Length(v.Search(&Options{Parent: "340000000000", Text: "湖"}), 1)
+
+	// 不存在的年份
+	v = db.At(2001)
+	a.Empty(v.Provinces()).
+		Nil(v.Find("330000000000")).
+		Empty(v.Search(&Options{Text: "温州"}))
+}